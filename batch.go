@@ -0,0 +1,94 @@
+// Copyright (c) 2016-2017. Oleg Sklyar & teris.io. All rights reserved.
+// See the LICENSE file in the project root for licensing information.
+
+package shortid
+
+import (
+	"errors"
+	"sync"
+)
+
+// randomPool recycles the buffers for the random component of single Ids, which otherwise escape
+// to the heap when passed to the source of randomness.
+var randomPool = sync.Pool{
+	New: func() interface{} {
+		return new([maxIDLen]byte)
+	},
+}
+
+// AppendGenerate generates a new short Id and appends it to dst, returning the extended buffer.
+// It otherwise acts just like Generate. For single-byte alphabets, such as DefaultABC, it does not
+// allocate if dst has sufficient capacity (Layout().Len() plus a few bytes for the counter).
+func (sid *Shortid) AppendGenerate(dst []byte) ([]byte, error) {
+	sid.checkExhaustion(sid.clock.Now())
+	return sid.appendNext(dst)
+}
+
+// appendNext reserves the next counter value and appends the Id to dst. Should the Id match the
+// blocklist whatever its random component, further counter values are reserved.
+func (sid *Shortid) appendNext(dst []byte) ([]byte, error) {
+	buf := randomPool.Get().(*[maxIDLen]byte)
+	defer randomPool.Put(buf)
+	random := buf[:sid.layout.Len()]
+	for attempt := 0; attempt < maxReservations; attempt++ {
+		ms, count, err := sid.reserve(1)
+		if err != nil {
+			return dst, err
+		}
+		if err := sid.abc.readRandom(random); err != nil {
+			return dst, err
+		}
+		res, ok, err := sid.appendAllowed(dst, ms, count, random)
+		if err != nil || ok {
+			return res, err
+		}
+	}
+	return dst, ErrBlocked
+}
+
+// GenerateN generates n new short Ids at once. The counter range for the batch is reserved with a
+// single lock-free compare-and-swap and the randomness for all Ids is drawn in a single read, while
+// the Ids are unique exactly like those generated by n calls to Generate. Ids of a batch share the millisecond and
+// thus all but the first are longer than Layout().Len() symbols.
+func (sid *Shortid) GenerateN(n int) ([]string, error) {
+	if n < 0 {
+		return nil, errors.New("expected non-negative number of Ids")
+	}
+	if n == 0 {
+		return []string{}, nil
+	}
+	sid.checkExhaustion(sid.clock.Now())
+	ms, first, err := sid.reserve(uint(n))
+	if err != nil {
+		return nil, err
+	}
+	size := sid.layout.Len()
+	random := make([]byte, n*size)
+	if err := sid.abc.readRandom(random); err != nil {
+		return nil, err
+	}
+	ids := make([]string, n)
+	buf := make([]byte, 0, 16)
+	for i := range ids {
+		var ok bool
+		if buf, ok, err = sid.appendAllowed(buf[:0], ms, first+uint(i), random[i*size:(i+1)*size]); err != nil {
+			return nil, err
+		}
+		if !ok {
+			if buf, err = sid.appendNext(buf[:0]); err != nil {
+				return nil, err
+			}
+		}
+		ids[i] = string(buf)
+	}
+	return ids, nil
+}
+
+// MustGenerateN acts just like GenerateN, but panics instead of returning errors.
+func (sid *Shortid) MustGenerateN(n int) []string {
+	ids, err := sid.GenerateN(n)
+	if err == nil {
+		return ids
+	}
+	panic(err)
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2016-2017. Oleg Sklyar & teris.io. All rights reserved.
+// See the LICENSE file in the project root for licensing information.
+
+package shortid_test
+
+import (
+	"github.com/teris-io/shortid"
+	"github.com/teris-io/shortid/shortidtest"
+	"testing"
+	"time"
+)
+
+func TestShortid_onGenerateN_uniqueWithConsecutiveCounters(t *testing.T) {
+	clock := shortidtest.NewClock(time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC))
+	sid, err := shortid.NewWithOptions(shortid.WithClock(clock), shortid.WithWorker(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := sid.MustGenerate()
+	ids, err := sid.GenerateN(1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := sid.MustGenerate()
+	ids = append(append([]string{first}, ids...), last)
+	unique := make(map[string]struct{}, len(ids))
+	for i, id := range ids {
+		unique[id] = struct{}{}
+		decoded, err := sid.Decode(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !decoded.Time.Equal(clock.Now()) || decoded.Worker != 3 || decoded.Counter != uint(i) {
+			t.Errorf("expected %v, worker 3 and counter %v, found %+v", clock.Now(), i, decoded)
+		}
+	}
+	if len(unique) != len(ids) {
+		t.Errorf("expected %v unique ids, found %v", len(ids), len(unique))
+	}
+}
+
+func TestShortid_onGenerateN_withZero_empty(t *testing.T) {
+	sid := shortid.MustNew(0, shortid.DefaultABC, 1)
+	if ids, err := sid.GenerateN(0); err != nil {
+		t.Error(err)
+	} else if len(ids) != 0 {
+		t.Errorf("expected no ids, found %v", ids)
+	}
+}
+
+func TestShortid_onGenerateN_withNegative_error(t *testing.T) {
+	sid := shortid.MustNew(0, shortid.DefaultABC, 1)
+	if _, err := sid.GenerateN(-1); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestShortid_onMustGenerateN_onError_panics(t *testing.T) {
+	sid := shortid.MustNew(0, shortid.DefaultABC, 1)
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic")
+		}
+	}()
+	sid.MustGenerateN(-1)
+}
+
+func TestShortid_onAppendGenerate_appendsToBuffer(t *testing.T) {
+	sid := shortid.MustNew(0, shortid.DefaultABC, 1)
+	buf, err := sid.AppendGenerate([]byte("id="))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(buf) < 12 || string(buf[:3]) != "id=" {
+		t.Errorf("expected id appended to prefix, found %v", string(buf))
+	}
+	if _, err := sid.Decode(string(buf[3:])); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestShortid_onGenerateN_beyondCounterCapacity_error(t *testing.T) {
+	sid := shortid.MustNew(0, shortid.DefaultABC, 1)
+	if _, err := sid.GenerateN(1<<24 + 1); err == nil {
+		t.Error("expected error")
+	}
+}
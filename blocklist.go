@@ -0,0 +1,109 @@
+// Copyright (c) 2016-2017. Oleg Sklyar & teris.io. All rights reserved.
+// See the LICENSE file in the project root for licensing information.
+
+package shortid
+
+import (
+	"bytes"
+	"errors"
+	"regexp"
+)
+
+// DefaultBlocklist is the built-in list of English profanities excluded from Ids by
+// WithDefaultBlocklist. Matching is case-insensitive and ignores word boundaries.
+var DefaultBlocklist = []string{
+	"anal", "anus", "arse", "ass", "bitch", "boob", "butt", "cock", "coon", "cum", "cunt", "dick",
+	"dildo", "fag", "fuck", "jizz", "kike", "nazi", "nigg", "penis", "piss", "porn", "puss", "rape",
+	"shit", "slut", "spic", "tits", "twat", "vagina", "wank", "whore",
+}
+
+// maxRerolls is the number of times the random component of an Id is chosen anew should the Id
+// match the blocklist.
+const maxRerolls = 16
+
+// maxReservations is the number of counter values tried for a single Id should every random
+// component match the blocklist, e.g. because the match is in the counter symbols.
+const maxReservations = 16
+
+// ErrBlocked is returned by Generate if no Id free of blocklisted words could be found. This is
+// practically impossible unless the blocklist contains very short words or patterns.
+var ErrBlocked = errors.New("cannot generate an Id free of blocklisted words")
+
+type blocklist struct {
+	words    [][]byte
+	patterns []*regexp.Regexp
+}
+
+// WithDefaultBlocklist excludes the words of DefaultBlocklist from generated Ids.
+func WithDefaultBlocklist() Option {
+	return WithBlocklist(DefaultBlocklist...)
+}
+
+// WithBlocklist excludes the given words (case-insensitive) from generated Ids. An Id containing
+// any of the words is generated anew choosing a different random component while keeping the
+// encoded millisecond, worker and counter, thus uniqueness is unaffected. Only if the match cannot
+// be avoided that way, e.g. as it is found in the counter symbols, the next counter value is used.
+func WithBlocklist(words ...string) Option {
+	return func(opts *options) error {
+		for _, word := range words {
+			if word == "" {
+				return errors.New("expected non-empty blocklisted word")
+			}
+			opts.block.words = append(opts.block.words, []byte(word))
+		}
+		return nil
+	}
+}
+
+// WithBlocklistPattern excludes Ids matching any of the given regular expressions, otherwise
+// acting just like WithBlocklist.
+func WithBlocklistPattern(patterns ...*regexp.Regexp) Option {
+	return func(opts *options) error {
+		for _, pattern := range patterns {
+			if pattern == nil {
+				return errors.New("expected non-nil blocklisted pattern")
+			}
+			opts.block.patterns = append(opts.block.patterns, pattern)
+		}
+		return nil
+	}
+}
+
+func (bl *blocklist) empty() bool {
+	return len(bl.words) == 0 && len(bl.patterns) == 0
+}
+
+func (bl *blocklist) match(id []byte) bool {
+	for _, word := range bl.words {
+		for i := 0; i+len(word) <= len(id); i++ {
+			if bytes.EqualFold(id[i:i+len(word)], word) {
+				return true
+			}
+		}
+	}
+	for _, pattern := range bl.patterns {
+		if pattern.Match(id) {
+			return true
+		}
+	}
+	return false
+}
+
+// appendAllowed appends the Id for the given millisecond and counter to dst choosing the random
+// component anew while the Id matches the blocklist. It returns false (and dst unchanged) if no
+// random component avoids the match.
+func (sid *Shortid) appendAllowed(dst []byte, ms, count uint, random []byte) ([]byte, bool, error) {
+	start := len(dst)
+	for reroll := 0; ; reroll++ {
+		dst = sid.appendID(dst[:start], ms, sid.worker, count, random)
+		if sid.block == nil || !sid.block.match(dst[start:]) {
+			return dst, true, nil
+		}
+		if reroll == maxRerolls || sid.sorted {
+			return dst[:start], false, nil
+		}
+		if err := sid.abc.readRandom(random); err != nil {
+			return dst[:start], false, err
+		}
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2016-2017. Oleg Sklyar & teris.io. All rights reserved.
+// See the LICENSE file in the project root for licensing information.
+
+package shortid_test
+
+import (
+	"errors"
+	"github.com/teris-io/shortid"
+	"github.com/teris-io/shortid/shortidtest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestShortid_withBlocklistPattern_rerollsKeepingData(t *testing.T) {
+	clock := shortidtest.NewClock(time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC))
+	sid, err := shortid.NewWithOptions(shortid.WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// block one of the two symbols encoding the first time symbol
+	pattern := regexp.MustCompile("^" + regexp.QuoteMeta(string([]rune(sid.MustGenerate())[0])))
+	sid, err = shortid.NewWithOptions(shortid.WithClock(clock), shortid.WithBlocklistPattern(pattern))
+	if err != nil {
+		t.Fatal(err)
+	}
+	counters := make(map[uint]struct{})
+	for _, id := range sid.MustGenerateN(1000) {
+		if pattern.MatchString(id) {
+			t.Errorf("expected %v not to match %v", id, pattern)
+		}
+		decoded, err := sid.Decode(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := counters[decoded.Counter]; ok || !decoded.Time.Equal(clock.Now()) {
+			t.Errorf("expected %v and unique counter, found %+v", clock.Now(), decoded)
+		}
+		counters[decoded.Counter] = struct{}{}
+	}
+}
+
+func TestShortid_withBlocklist_caseInsensitive(t *testing.T) {
+	sid, err := shortid.NewWithOptions(shortid.WithBlocklist("Q"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range sid.MustGenerateN(1000) {
+		if strings.ContainsAny(id, "qQ") {
+			t.Errorf("expected no q in %v", id)
+		}
+	}
+}
+
+func TestShortid_withBlocklistPattern_inCounter_skipsCounterValue(t *testing.T) {
+	clock := shortidtest.NewClock(time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC))
+	alphabet := []rune(shortid.MustNewAbc(shortid.DefaultABC, 1).Alphabet())
+	// counter 1 is encoded by the symbol at index 1 without randomness
+	pattern := regexp.MustCompile("^.{9}" + regexp.QuoteMeta(string(alphabet[1])) + "$")
+	sid, err := shortid.NewWithOptions(shortid.WithClock(clock), shortid.WithBlocklistPattern(pattern))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var counters []uint
+	for i := 0; i < 3; i++ {
+		decoded, err := sid.Decode(sid.MustGenerate())
+		if err != nil {
+			t.Fatal(err)
+		}
+		counters = append(counters, decoded.Counter)
+	}
+	if counters[0] != 0 || counters[1] != 2 || counters[2] != 3 {
+		t.Errorf("expected counters [0 2 3], found %v", counters)
+	}
+	ids := sid.MustGenerateN(3)
+	if len(ids) != 3 {
+		t.Fatalf("expected 3 ids, found %v", ids)
+	}
+	for _, id := range ids {
+		if pattern.MatchString(id) {
+			t.Errorf("expected %v not to match %v", id, pattern)
+		}
+	}
+}
+
+func TestShortid_withBlocklistPattern_unavoidable_errBlocked(t *testing.T) {
+	sid, err := shortid.NewWithOptions(shortid.WithBlocklistPattern(regexp.MustCompile(".")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sid.Generate(); !errors.Is(err, shortid.ErrBlocked) {
+		t.Errorf("expected ErrBlocked, found %v", err)
+	}
+}
+
+func TestShortid_withDefaultBlocklist_success(t *testing.T) {
+	sid, err := shortid.NewWithOptions(shortid.WithDefaultBlocklist())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range sid.MustGenerateN(10000) {
+		lower := strings.ToLower(id)
+		for _, word := range shortid.DefaultBlocklist {
+			if strings.Contains(lower, word) {
+				t.Errorf("expected no '%v' in %v", word, id)
+			}
+		}
+	}
+}
+
+func TestShortid_withBlocklist_emptyWord_error(t *testing.T) {
+	if _, err := shortid.NewWithOptions(shortid.WithBlocklist("")); err == nil {
+		t.Error("expected error")
+	}
+	if _, err := shortid.NewWithOptions(shortid.WithBlocklistPattern(nil)); err == nil {
+		t.Error("expected error")
+	}
+}
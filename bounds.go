@@ -0,0 +1,59 @@
+// Copyright (c) 2016-2017. Oleg Sklyar & teris.io. All rights reserved.
+// See the LICENSE file in the project root for licensing information.
+
+package shortid
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RangeBounds returns the bounds of the Ids generated within [from, to) by sortable generators
+// sharing the alphabet and epoch of this one (see WithSortable): exactly those Ids satisfy
+// lo <= id < hi in byte-wise comparison, e.g. in the query
+//
+//	SELECT ... WHERE id >= $lo AND id < $hi
+//
+// Times before the epoch are clamped to the epoch and times after the end of the life span to the
+// end of the life span. It fails for generators that are not sortable; use CreatedBetween instead.
+func (sid *Shortid) RangeBounds(from, to time.Time) (string, string, error) {
+	if !sid.sorted {
+		return "", "", errors.New("range bounds require a sortable generator")
+	}
+	if !from.Before(to) {
+		return "", "", fmt.Errorf("expected %v before %v", from, to)
+	}
+	return sid.bound(from), sid.bound(to), nil
+}
+
+// bound returns the smallest Id generated at or after the time.
+func (sid *Shortid) bound(tm time.Time) string {
+	l := sid.layout
+	var ms uint
+	if elapsed := tm.Sub(sid.epoch); elapsed >= l.Lifespan() {
+		ms = uint(l.Lifespan() / time.Millisecond)
+	} else if elapsed > 0 {
+		// Ids carry whole milliseconds, so round up to the first one within the range
+		ms = uint((elapsed + time.Millisecond - 1) / time.Millisecond)
+	}
+	if ms >= uint(l.Lifespan()/time.Millisecond) {
+		// the first symbol beyond the range of time symbols orders after all Ids
+		return string(sid.abc.alphabet[1<<l.Digits])
+	}
+	dst := make([]byte, 0, 16)
+	dst = sid.abc.appendSorted(dst, ms, uint(l.TimeSymbols), l.Digits)
+	dst = sid.abc.appendSorted(dst, 0, uint(l.WorkerSymbols), l.Digits)
+	return string(dst)
+}
+
+// CreatedBetween reports whether the Id of this or an equally configured generator was generated
+// within [from, to), judging by the millisecond it carries. Unlike RangeBounds it works with any
+// layout, but requires decoding every Id.
+func (sid *Shortid) CreatedBetween(id string, from, to time.Time) (bool, error) {
+	decoded, err := sid.Decode(id)
+	if err != nil {
+		return false, err
+	}
+	return !decoded.Time.Before(from) && decoded.Time.Before(to), nil
+}
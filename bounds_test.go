@@ -0,0 +1,105 @@
+// Copyright (c) 2016-2017. Oleg Sklyar & teris.io. All rights reserved.
+// See the LICENSE file in the project root for licensing information.
+
+package shortid_test
+
+import (
+	"github.com/teris-io/shortid"
+	"github.com/teris-io/shortid/shortidtest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestShortid_RangeBounds_matchCreatedBetween(t *testing.T) {
+	start := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	clock := shortidtest.NewClock(start)
+	var sids []*shortid.Shortid
+	for _, worker := range []uint{0, 5, 31} {
+		sids = append(sids, newSortable(t, clock, shortid.WithWorker(worker)))
+	}
+	var ids []string
+	for ms := 0; ms < 50; ms++ {
+		for _, sid := range sids {
+			ids = append(ids, sid.MustGenerateN(ms%3+1)...)
+		}
+		clock.Advance(time.Millisecond)
+	}
+	sid := sids[0]
+	for _, r := range [][2]time.Duration{
+		{10 * time.Millisecond, 20 * time.Millisecond},
+		{10*time.Millisecond + 500*time.Microsecond, 20*time.Millisecond + 1},
+		{-time.Hour, 5 * time.Millisecond},
+		{45 * time.Millisecond, 100 * 365 * 24 * time.Hour},
+	} {
+		from, to := start.Add(r[0]), start.Add(r[1])
+		lo, hi, err := sid.RangeBounds(from, to)
+		if err != nil {
+			t.Fatal(err)
+		}
+		n := 0
+		for _, id := range ids {
+			between, err := sid.CreatedBetween(id, from, to)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if inBounds := lo <= id && id < hi; inBounds != between {
+				t.Errorf("[%v,%v): expected %v within bounds [%v,%v) to be %v", r[0], r[1], id, lo, hi, between)
+			}
+			if between {
+				n++
+			}
+		}
+		if n == 0 {
+			t.Errorf("[%v,%v): expected Ids in range", r[0], r[1])
+		}
+	}
+}
+
+func TestShortid_RangeBounds_onBeforeEpoch_clamped(t *testing.T) {
+	sid := newSortable(t, shortid.SystemClock{})
+	lo, _, err := sid.RangeBounds(time.Time{}, sid.Epoch().Add(time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	zero := sid.Abc().Alphabet()[:1]
+	if expected := strings.Repeat(zero, sid.Layout().Len()); lo != expected {
+		t.Errorf("expected %v, found %v", expected, lo)
+	}
+}
+
+func TestShortid_RangeBounds_onNonSortableOrEmptyRange_error(t *testing.T) {
+	now := time.Now()
+	if _, _, err := shortid.GetDefault().RangeBounds(now, now.Add(time.Hour)); err == nil {
+		t.Error("expected error for non-sortable generator")
+	}
+	sid := newSortable(t, shortid.SystemClock{})
+	if _, _, err := sid.RangeBounds(now, now); err == nil {
+		t.Error("expected error for empty range")
+	}
+}
+
+func TestShortid_CreatedBetween(t *testing.T) {
+	clock := shortidtest.NewClock(time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC))
+	sid, err := shortid.NewWithOptions(shortid.WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := sid.MustGenerate()
+	now := clock.Now()
+	for _, tc := range []struct {
+		from, to time.Time
+		expected bool
+	}{
+		{now, now.Add(time.Millisecond), true},
+		{now.Add(-time.Hour), now, false},
+		{now.Add(time.Millisecond), now.Add(time.Hour), false},
+	} {
+		if between, err := sid.CreatedBetween(id, tc.from, tc.to); err != nil || between != tc.expected {
+			t.Errorf("[%v,%v): expected %v, found %v, %v", tc.from, tc.to, tc.expected, between, err)
+		}
+	}
+	if _, err := sid.CreatedBetween("a*", now, now); err == nil {
+		t.Error("expected error")
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2016-2017. Oleg Sklyar & teris.io. All rights reserved.
+// See the LICENSE file in the project root for licensing information.
+
+package shortid
+
+import (
+	"errors"
+	"unicode"
+)
+
+// CaseInsensitiveABC is a 32-symbol alphabet of digits and lower-case letters for Ids that pass
+// through case-folding systems such as DNS labels or file names on Windows. Its Ids are 12 symbols
+// long with the same 34-year life span as the default, see NewCaseInsensitiveAbc.
+const CaseInsensitiveABC = "0123456789abcdefghijklmnopqrstuv"
+
+// NewCaseInsensitiveAbc constructs a shuffled alphabet treating symbols case-insensitively. The
+// alphabet is folded to lower case dropping the symbols that only differ in case. If the number of
+// remaining symbols is not a power of two, the alphabet is truncated to the largest power of two,
+// e.g. DefaultABC folds to 38 symbols of which the first 32 are used. Encoding produces lower-case
+// symbols only while decoding accepts either case, thus uniqueness holds after case-folding at the
+// cost of longer Ids for the smaller alphabet.
+func NewCaseInsensitiveAbc(alphabet string, seed uint64) (Abc, error) {
+	var runes []rune
+	found := make(map[rune]struct{})
+	for _, r := range alphabet {
+		r = unicode.ToLower(r)
+		if _, seen := found[r]; !seen {
+			found[r] = struct{}{}
+			runes = append(runes, r)
+		}
+	}
+	size := 1 << maxAbcBits
+	for size > len(runes) {
+		size >>= 1
+	}
+	if size < 1<<minAbcBits {
+		return Abc{}, errors.New("case-insensitive alphabet must contain at least 16 unique characters")
+	}
+	abc, err := NewAbc(string(runes[:size]), seed)
+	if err != nil {
+		return Abc{}, err
+	}
+	abc.fold = true
+	return abc, nil
+}
+
+// CaseInsensitive reports whether the alphabet treats symbols case-insensitively.
+func (abc Abc) CaseInsensitive() bool {
+	return abc.fold
+}
+
+// WithCaseInsensitive makes the generator treat Ids case-insensitively, see NewCaseInsensitiveAbc:
+// the alphabet set with WithAlphabet is folded to lower case, the Layout is adjusted to the
+// resulting alphabet size and Decode and Validate accept Ids in either case.
+func WithCaseInsensitive() Option {
+	return func(opts *options) error {
+		opts.fold = true
+		return nil
+	}
+}
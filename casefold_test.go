@@ -0,0 +1,80 @@
+// Copyright (c) 2016-2017. Oleg Sklyar & teris.io. All rights reserved.
+// See the LICENSE file in the project root for licensing information.
+
+package shortid_test
+
+import (
+	"github.com/teris-io/shortid"
+	"strings"
+	"testing"
+)
+
+func TestAbc_onNewCaseInsensitiveAbc_foldsAndTruncates(t *testing.T) {
+	abc, err := shortid.NewCaseInsensitiveAbc(shortid.DefaultABC, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !abc.CaseInsensitive() {
+		t.Error("expected case-insensitive alphabet")
+	}
+	alphabet := abc.Alphabet()
+	if len(alphabet) != 32 || strings.ToLower(alphabet) != alphabet {
+		t.Errorf("expected 32 lower-case symbols, found %v", alphabet)
+	}
+	for _, r := range shortid.CaseInsensitiveABC {
+		if !strings.ContainsRune(alphabet, r) {
+			t.Errorf("expected %c in %v", r, alphabet)
+		}
+	}
+}
+
+func TestAbc_onNewCaseInsensitiveAbc_tooFewSymbols_error(t *testing.T) {
+	if _, err := shortid.NewCaseInsensitiveAbc("abcdefghABCDEFGH", 1); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestAbc_onDecode_caseInsensitive_acceptsEitherCase(t *testing.T) {
+	abc := shortid.MustNewAbc(shortid.CaseInsensitiveABC, 1)
+	folded, err := shortid.NewCaseInsensitiveAbc(shortid.CaseInsensitiveABC, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	runes := folded.MustEncode(123456, 0, 5)
+	upper := []rune(strings.ToUpper(string(runes)))
+	if val, err := folded.Decode(upper, 5); err != nil {
+		t.Error(err)
+	} else if val != 123456 {
+		t.Errorf("expected 123456, found %v", val)
+	}
+	if _, err := abc.Decode(upper, 5); err == nil {
+		t.Error("expected error for case-sensitive alphabet")
+	}
+}
+
+func TestShortid_withCaseInsensitive_uniqueAfterCaseFolding(t *testing.T) {
+	sid, err := shortid.NewWithOptions(shortid.WithCaseInsensitive(), shortid.WithWorker(17))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sid.Layout().Len() != 12 {
+		t.Errorf("expected Ids of 12 symbols, found %v", sid.Layout())
+	}
+	ids := sid.MustGenerateN(10000)
+	unique := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		unique[strings.ToLower(id)] = struct{}{}
+		upper := strings.ToUpper(id)
+		if err := sid.Validate(upper); err != nil {
+			t.Error(err)
+		}
+		if decoded, err := sid.Decode(upper); err != nil {
+			t.Error(err)
+		} else if decoded.Worker != 17 {
+			t.Errorf("expected worker 17, found %v", decoded.Worker)
+		}
+	}
+	if len(unique) != len(ids) {
+		t.Errorf("expected %v unique ids after case-folding, found %v", len(ids), len(unique))
+	}
+}
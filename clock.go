@@ -0,0 +1,76 @@
+// Copyright (c) 2016-2017. Oleg Sklyar & teris.io. All rights reserved.
+// See the LICENSE file in the project root for licensing information.
+
+package shortid
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Clock provides the current time to a generator and lets it wait for the time to pass. Every
+// Generate call reads the time from the clock of the generator.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// Sleep pauses the calling goroutine for at least the given duration.
+	Sleep(d time.Duration)
+}
+
+// SystemClock is the Clock backed by the system wall clock (default).
+type SystemClock struct{}
+
+// Now returns the current system time.
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// Sleep pauses the calling goroutine for at least the given duration.
+func (SystemClock) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
+
+// WithClock sets the source of the current time (default: SystemClock). The shortidtest package
+// provides a controllable implementation for testing.
+func WithClock(clock Clock) Option {
+	return func(opts *options) error {
+		if clock == nil {
+			return errors.New("expected non-nil clock")
+		}
+		opts.clock = clock
+		return nil
+	}
+}
+
+// ClockPolicy defines how a generator reacts to the wall clock moving backwards, e.g. when NTP
+// steps the clock or a virtual machine resumes from suspension. Reusing a millisecond that has
+// already been used with the counter reset would produce duplicate Ids, thus every policy
+// preserves uniqueness.
+type ClockPolicy int
+
+const (
+	// ClockKeepLast keeps issuing Ids from the last used millisecond with an incrementing counter
+	// until the clock catches up (default). Ids issued meanwhile are longer than 9 symbols.
+	ClockKeepLast ClockPolicy = iota
+	// ClockWait blocks Generate until the clock catches up with the last used millisecond.
+	ClockWait
+	// ClockFail makes Generate return ErrClockMovedBackwards until the clock catches up with the
+	// last used millisecond.
+	ClockFail
+)
+
+// ErrClockMovedBackwards is returned by Generate under the ClockFail policy if the clock is behind
+// the last used millisecond.
+var ErrClockMovedBackwards = errors.New("clock moved backwards")
+
+// WithClockPolicy sets the reaction to the clock moving backwards (default: ClockKeepLast).
+func WithClockPolicy(policy ClockPolicy) Option {
+	return func(opts *options) error {
+		if policy < ClockKeepLast || ClockFail < policy {
+			return fmt.Errorf("unknown clock policy %v", policy)
+		}
+		opts.policy = policy
+		return nil
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2016-2017. Oleg Sklyar & teris.io. All rights reserved.
+// See the LICENSE file in the project root for licensing information.
+
+package shortid_test
+
+import (
+	"errors"
+	"github.com/teris-io/shortid"
+	"github.com/teris-io/shortid/shortidtest"
+	"testing"
+	"time"
+)
+
+func newWithClock(t *testing.T, policy shortid.ClockPolicy) (*shortid.Shortid, *shortidtest.Clock) {
+	clock := shortidtest.NewClock(time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC))
+	sid, err := shortid.NewWithOptions(shortid.WithClock(clock), shortid.WithClockPolicy(policy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sid, clock
+}
+
+func TestShortid_onGenerate_clockBackwards_keepLast_uniqueFromLastMs(t *testing.T) {
+	sid, clock := newWithClock(t, shortid.ClockKeepLast)
+	last := sid.MustGenerate()
+	clock.Advance(-time.Second)
+	ids := map[string]struct{}{last: {}}
+	for i := 1; i <= 10; i++ {
+		id := sid.MustGenerate()
+		if _, ok := ids[id]; ok {
+			t.Fatalf("duplicate %v", id)
+		}
+		ids[id] = struct{}{}
+		decoded := must(sid.Decode(id))
+		if decoded.Ms != must(sid.Decode(last)).Ms || decoded.Counter != uint(i) {
+			t.Errorf("expected last ms with counter %v, found %+v", i, decoded)
+		}
+	}
+}
+
+func TestShortid_onGenerate_clockBackwards_wait_sleepsUntilCaughtUp(t *testing.T) {
+	sid, clock := newWithClock(t, shortid.ClockWait)
+	last := must(sid.Decode(sid.MustGenerate()))
+	clock.Advance(-time.Second)
+	decoded := must(sid.Decode(sid.MustGenerate()))
+	if !clock.Now().Equal(last.Time) {
+		t.Errorf("expected to sleep until %v, found %v", last.Time, clock.Now())
+	}
+	if decoded.Ms != last.Ms || decoded.Counter != 1 {
+		t.Errorf("expected last ms with counter 1, found %+v", decoded)
+	}
+}
+
+func TestShortid_onGenerate_clockBackwards_fail_error(t *testing.T) {
+	sid, clock := newWithClock(t, shortid.ClockFail)
+	sid.MustGenerate()
+	clock.Advance(-time.Millisecond)
+	if _, err := sid.Generate(); !errors.Is(err, shortid.ErrClockMovedBackwards) {
+		t.Errorf("expected ErrClockMovedBackwards, found %v", err)
+	}
+	clock.Advance(time.Millisecond)
+	if _, err := sid.Generate(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestShortid_onNewWithOptions_withUnknownClockPolicy_error(t *testing.T) {
+	if _, err := shortid.NewWithOptions(shortid.WithClockPolicy(shortid.ClockPolicy(5))); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func must(decoded shortid.DecodedID, err error) shortid.DecodedID {
+	if err != nil {
+		panic(err)
+	}
+	return decoded
+}
+
+func TestShortid_onNewWithOptions_withNilClock_error(t *testing.T) {
+	if _, err := shortid.NewWithOptions(shortid.WithClock(nil)); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestShortid_onNewWithOptions_epochValidatedAgainstClock(t *testing.T) {
+	epoch := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	clock := shortidtest.NewClock(epoch.Add(-time.Hour))
+	if _, err := shortid.NewWithOptions(shortid.WithClock(clock), shortid.WithEpoch(epoch)); err == nil {
+		t.Error("expected error")
+	}
+	clock.Advance(time.Hour)
+	if _, err := shortid.NewWithOptions(shortid.WithClock(clock), shortid.WithEpoch(epoch)); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,229 @@
+// Copyright (c) 2016-2017. Oleg Sklyar & teris.io. All rights reserved.
+// See the LICENSE file in the project root for licensing information.
+
+// Command shortid generates and inspects short Ids:
+//
+//	shortid gen [-n count] [-worker w] [-alphabet abc] [-seed s] [-epoch date] [-case-insensitive] [-sortable]
+//	shortid decode [-json] [generator flags] id...
+//	shortid validate [generator flags] id...
+//	shortid abc [-alphabet abc] [-seed s]
+//
+// Ids can only be decoded and validated with the alphabet, seed and epoch they were generated with.
+// validate exits with status 1 if any of the Ids is invalid, all commands exit with status 2 on
+// usage errors.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/teris-io/shortid"
+	"io"
+	"os"
+	"time"
+)
+
+const usage = `usage: shortid <command> [flags] [ids]
+
+commands:
+  gen       generate Ids
+  decode    print time, worker and counter of Ids
+  validate  check Ids, exit with status 1 if any is invalid
+  abc       print the shuffled alphabet
+
+run 'shortid <command> -h' for the flags of a command
+`
+
+// exit statuses
+const (
+	exitOK      = 0
+	exitInvalid = 1
+	exitUsage   = 2
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+	cmds := map[string]func([]string, io.Writer, io.Writer) int{
+		"gen":      gen,
+		"decode":   decode,
+		"validate": validate,
+		"abc":      abc,
+	}
+	cmd, ok := cmds[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "unknown command %q\n%v", args[0], usage)
+		return exitUsage
+	}
+	return cmd(args[1:], stdout, stderr)
+}
+
+// generatorFlags are the flags configuring the generator shared by gen, decode and validate.
+type generatorFlags struct {
+	worker   uint
+	alphabet string
+	seed     uint64
+	epoch    string
+	fold     bool
+	sortable bool
+}
+
+func (gf *generatorFlags) register(fs *flag.FlagSet) {
+	fs.UintVar(&gf.worker, "worker", 0, "worker number")
+	fs.StringVar(&gf.alphabet, "alphabet", shortid.DefaultABC, "alphabet of 16 to 256 unique symbols")
+	fs.Uint64Var(&gf.seed, "seed", 1, "seed of the alphabet shuffle")
+	fs.StringVar(&gf.epoch, "epoch", shortid.DefaultEpoch.Format("2006-01-02"), "epoch as date or RFC 3339 time")
+	fs.BoolVar(&gf.fold, "case-insensitive", false, "fold the alphabet to lower case")
+	fs.BoolVar(&gf.sortable, "sortable", false, "K-sortable Ids")
+}
+
+func (gf *generatorFlags) newShortid() (*shortid.Shortid, error) {
+	epoch, err := time.Parse(time.RFC3339Nano, gf.epoch)
+	if err != nil {
+		if epoch, err = time.Parse("2006-01-02", gf.epoch); err != nil {
+			return nil, fmt.Errorf("invalid epoch %q", gf.epoch)
+		}
+	}
+	opts := []shortid.Option{
+		shortid.WithWorker(gf.worker),
+		shortid.WithAlphabet(gf.alphabet),
+		shortid.WithSeed(gf.seed),
+		shortid.WithEpoch(epoch),
+	}
+	if gf.fold {
+		opts = append(opts, shortid.WithCaseInsensitive())
+	}
+	if gf.sortable {
+		opts = append(opts, shortid.WithSortable())
+	}
+	return shortid.NewWithOptions(opts...)
+}
+
+// parse parses the flags of a command reporting whether to continue and the exit status otherwise.
+func parse(fs *flag.FlagSet, args []string, stderr io.Writer) (bool, int) {
+	fs.SetOutput(stderr)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return false, exitOK
+		}
+		return false, exitUsage
+	}
+	return true, exitOK
+}
+
+func gen(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("gen", flag.ContinueOnError)
+	var gf generatorFlags
+	gf.register(fs)
+	n := fs.Int("n", 1, "number of Ids to generate")
+	if ok, status := parse(fs, args, stderr); !ok {
+		return status
+	}
+	if fs.NArg() > 0 || *n < 0 {
+		fmt.Fprintln(stderr, "gen expects a non-negative count and no arguments")
+		return exitUsage
+	}
+	sid, err := gf.newShortid()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+	for i := 0; i < *n; i++ {
+		id, err := sid.Generate()
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitInvalid
+		}
+		fmt.Fprintln(stdout, id)
+	}
+	return exitOK
+}
+
+// decoded is the JSON representation of a decoded Id.
+type decoded struct {
+	ID      string    `json:"id"`
+	Time    time.Time `json:"time"`
+	Ms      uint      `json:"ms"`
+	Worker  uint      `json:"worker"`
+	Counter uint      `json:"counter"`
+}
+
+func decode(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("decode", flag.ContinueOnError)
+	var gf generatorFlags
+	gf.register(fs)
+	asJSON := fs.Bool("json", false, "print one JSON object per Id")
+	if ok, status := parse(fs, args, stderr); !ok {
+		return status
+	}
+	sid, err := gf.newShortid()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+	status := exitOK
+	enc := json.NewEncoder(stdout)
+	for _, id := range fs.Args() {
+		d, err := sid.Decode(id)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			status = exitInvalid
+			continue
+		}
+		if *asJSON {
+			_ = enc.Encode(decoded{ID: id, Time: d.Time.UTC(), Ms: d.Ms, Worker: d.Worker, Counter: d.Counter})
+		} else {
+			fmt.Fprintf(stdout, "%v time=%v worker=%v counter=%v\n", id, d.Time.UTC().Format(time.RFC3339Nano), d.Worker, d.Counter)
+		}
+	}
+	return status
+}
+
+func validate(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	var gf generatorFlags
+	gf.register(fs)
+	if ok, status := parse(fs, args, stderr); !ok {
+		return status
+	}
+	if fs.NArg() == 0 {
+		fmt.Fprintln(stderr, "validate expects at least one Id")
+		return exitUsage
+	}
+	sid, err := gf.newShortid()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+	status := exitOK
+	for _, id := range fs.Args() {
+		if err := sid.Validate(id); err != nil {
+			fmt.Fprintln(stderr, err)
+			status = exitInvalid
+		}
+	}
+	return status
+}
+
+func abc(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("abc", flag.ContinueOnError)
+	alphabet := fs.String("alphabet", shortid.DefaultABC, "alphabet of 16 to 256 unique symbols")
+	seed := fs.Uint64("seed", 1, "seed of the alphabet shuffle")
+	if ok, status := parse(fs, args, stderr); !ok {
+		return status
+	}
+	a, err := shortid.NewAbc(*alphabet, *seed)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+	fmt.Fprintln(stdout, a.Alphabet())
+	return exitOK
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2016-2017. Oleg Sklyar & teris.io. All rights reserved.
+// See the LICENSE file in the project root for licensing information.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/teris-io/shortid"
+	"strings"
+	"testing"
+)
+
+func runCmd(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	status := run(args, &stdout, &stderr)
+	return status, stdout.String(), stderr.String()
+}
+
+func TestRun_genDecodeValidate(t *testing.T) {
+	status, out, errs := runCmd("gen", "-n", "3", "-worker", "7", "-seed", "2342")
+	if status != exitOK {
+		t.Fatalf("expected status 0, found %v: %v", status, errs)
+	}
+	ids := strings.Fields(out)
+	if len(ids) != 3 {
+		t.Fatalf("expected 3 Ids, found %v", ids)
+	}
+	status, out, errs = runCmd(append([]string{"decode", "-json", "-seed", "2342"}, ids...)...)
+	if status != exitOK {
+		t.Fatalf("expected status 0, found %v: %v", status, errs)
+	}
+	dec := json.NewDecoder(strings.NewReader(out))
+	for _, id := range ids {
+		var d decoded
+		if err := dec.Decode(&d); err != nil {
+			t.Fatal(err)
+		}
+		if d.ID != id || d.Worker != 7 {
+			t.Errorf("expected %v with worker 7, found %+v", id, d)
+		}
+	}
+	if status, _, errs = runCmd(append([]string{"validate", "-seed", "2342"}, ids...)...); status != exitOK {
+		t.Errorf("expected status 0, found %v: %v", status, errs)
+	}
+}
+
+func TestRun_decodeText(t *testing.T) {
+	status, out, _ := runCmd("decode", "ggWP3JLZg")
+	if status != exitOK || !strings.HasPrefix(out, "ggWP3JLZg time=") || !strings.Contains(out, "worker=") {
+		t.Errorf("expected decoded Id, found %v: %v", status, out)
+	}
+}
+
+func TestRun_onInvalidId_exitInvalid(t *testing.T) {
+	status, _, errs := runCmd("validate", "ggWP3JLZg", "ggW*3JLZg")
+	if status != exitInvalid {
+		t.Errorf("expected status 1, found %v", status)
+	}
+	if !strings.Contains(errs, "ggW*3JLZg") {
+		t.Errorf("expected invalid Id reported, found %v", errs)
+	}
+}
+
+func TestRun_abc(t *testing.T) {
+	status, out, _ := runCmd("abc", "-seed", "2342")
+	if expected := shortid.MustNewAbc(shortid.DefaultABC, 2342).Alphabet() + "\n"; status != exitOK || out != expected {
+		t.Errorf("expected %v, found %v: %v", expected, status, out)
+	}
+}
+
+func TestRun_onUsageErrors_exitUsage(t *testing.T) {
+	for _, args := range [][]string{
+		{},
+		{"unknown"},
+		{"gen", "-n", "-1"},
+		{"gen", "-bogus"},
+		{"validate"},
+		{"gen", "-alphabet", "abc"},
+		{"gen", "-epoch", "yesterday"},
+	} {
+		if status, _, _ := runCmd(args...); status != exitUsage {
+			t.Errorf("%v: expected status 2, found %v", args, status)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2016-2017. Oleg Sklyar & teris.io. All rights reserved.
+// See the LICENSE file in the project root for licensing information.
+
+package shortid
+
+import (
+	"sort"
+	"strings"
+)
+
+// Compare orders two Ids of this or an equally configured generator chronologically by their
+// (millisecond, worker, counter), returning -1, 0 or +1 like strings.Compare. It works with any
+// layout, not only with sortable Ids (see WithSortable), and returns a *ValidationError if either
+// Id cannot be decoded.
+func (sid *Shortid) Compare(a, b string) (int, error) {
+	da, err := sid.Decode(a)
+	if err != nil {
+		return 0, err
+	}
+	db, err := sid.Decode(b)
+	if err != nil {
+		return 0, err
+	}
+	return compareDecoded(da, db), nil
+}
+
+// Comparator returns a comparison function for slices.SortFunc and the like ordering Ids as
+// Compare does. Ids that cannot be decoded order before all valid Ids and among each other as
+// strings.
+func (sid *Shortid) Comparator() func(a, b string) int {
+	return func(a, b string) int {
+		da, erra := sid.Decode(a)
+		db, errb := sid.Decode(b)
+		return compareKeys(da, erra == nil, a, db, errb == nil, b)
+	}
+}
+
+// ByTime returns a sort.Interface ordering the Ids in place as Comparator does, decoding each Id
+// once up front:
+//
+//	sort.Sort(sid.ByTime(ids))
+func (sid *Shortid) ByTime(ids []string) sort.Interface {
+	s := &byTime{ids: ids, keys: make([]DecodedID, len(ids)), valid: make([]bool, len(ids))}
+	for i, id := range ids {
+		var err error
+		s.keys[i], err = sid.Decode(id)
+		s.valid[i] = err == nil
+	}
+	return s
+}
+
+type byTime struct {
+	ids   []string
+	keys  []DecodedID
+	valid []bool
+}
+
+func (s *byTime) Len() int {
+	return len(s.ids)
+}
+
+func (s *byTime) Less(i, j int) bool {
+	return compareKeys(s.keys[i], s.valid[i], s.ids[i], s.keys[j], s.valid[j], s.ids[j]) < 0
+}
+
+func (s *byTime) Swap(i, j int) {
+	s.ids[i], s.ids[j] = s.ids[j], s.ids[i]
+	s.keys[i], s.keys[j] = s.keys[j], s.keys[i]
+	s.valid[i], s.valid[j] = s.valid[j], s.valid[i]
+}
+
+func compareKeys(da DecodedID, va bool, a string, db DecodedID, vb bool, b string) int {
+	switch {
+	case va && vb:
+		return compareDecoded(da, db)
+	case va:
+		return 1
+	case vb:
+		return -1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+func compareDecoded(a, b DecodedID) int {
+	switch {
+	case a.Ms != b.Ms:
+		return compareUint(a.Ms, b.Ms)
+	case a.Worker != b.Worker:
+		return compareUint(a.Worker, b.Worker)
+	default:
+		return compareUint(a.Counter, b.Counter)
+	}
+}
+
+func compareUint(a, b uint) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2016-2017. Oleg Sklyar & teris.io. All rights reserved.
+// See the LICENSE file in the project root for licensing information.
+
+package shortid_test
+
+import (
+	"errors"
+	"github.com/teris-io/shortid"
+	"github.com/teris-io/shortid/shortidtest"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+// mergedIds generates Ids on two workers sharing a clock, returning them in generation order.
+func mergedIds(t *testing.T) (*shortid.Shortid, []string) {
+	clock := shortidtest.NewClock(time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC))
+	first, err := shortid.NewWithOptions(shortid.WithClock(clock), shortid.WithWorker(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := shortid.NewWithOptions(shortid.WithClock(clock), shortid.WithWorker(9))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for ms := 0; ms < 20; ms++ {
+		ids = append(ids, first.MustGenerateN(ms%4+1)...)
+		ids = append(ids, second.MustGenerateN(ms%3+1)...)
+		clock.Advance(time.Duration(ms%2+1) * time.Millisecond)
+	}
+	return first, ids
+}
+
+func shuffled(ids []string) []string {
+	res := append([]string(nil), ids...)
+	rand.New(rand.NewSource(42)).Shuffle(len(res), func(i, j int) { res[i], res[j] = res[j], res[i] })
+	return res
+}
+
+func TestShortid_Compare(t *testing.T) {
+	sid, ids := mergedIds(t)
+	for i := 1; i < len(ids); i++ {
+		if c, err := sid.Compare(ids[i-1], ids[i]); err != nil || c != -1 {
+			t.Errorf("expected %v before %v, found %v, %v", ids[i-1], ids[i], c, err)
+		}
+		if c, err := sid.Compare(ids[i], ids[i-1]); err != nil || c != 1 {
+			t.Errorf("expected %v after %v, found %v, %v", ids[i], ids[i-1], c, err)
+		}
+	}
+	if c, err := sid.Compare(ids[0], ids[0]); err != nil || c != 0 {
+		t.Errorf("expected equal, found %v, %v", c, err)
+	}
+	if _, err := sid.Compare(ids[0], "a*b"); !errors.Is(err, shortid.ErrInvalidSymbol) {
+		t.Errorf("expected ErrInvalidSymbol, found %v", err)
+	}
+}
+
+func TestShortid_ByTime(t *testing.T) {
+	sid, ids := mergedIds(t)
+	sorted := shuffled(ids)
+	sort.Sort(sid.ByTime(sorted))
+	if !reflect.DeepEqual(sorted, ids) {
+		t.Errorf("expected %v, found %v", ids, sorted)
+	}
+}
+
+func TestShortid_Comparator(t *testing.T) {
+	sid, ids := mergedIds(t)
+	cmp := sid.Comparator()
+	sorted := append(shuffled(ids), "b*", "a*")
+	sort.Slice(sorted, func(i, j int) bool { return cmp(sorted[i], sorted[j]) < 0 })
+	expected := append([]string{"a*", "b*"}, ids...)
+	if !reflect.DeepEqual(sorted, expected) {
+		t.Errorf("expected %v, found %v", expected, sorted)
+	}
+}
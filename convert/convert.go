@@ -0,0 +1,78 @@
+// Copyright (c) 2016-2017. Oleg Sklyar & teris.io. All rights reserved.
+// See the LICENSE file in the project root for licensing information.
+
+// Package convert maps short Ids to and from UUIDv7, ULID and Snowflake Ids carrying the same
+// millisecond, worker and counter, so that short Ids can remain the public identifiers while the
+// canonical types are stored internally:
+//
+//	u, err := convert.ToUUIDv7(sid, id)
+//	...
+//	id, err = convert.FromUUIDv7(sid, u)
+//
+// Conversions are deterministic and preserve the millisecond, the worker and the counter of Ids of
+// the given generator (or an equally configured one): the bits of the target formats that are not
+// needed for the worker and the counter are zero. Converting back yields an Id with the same
+// millisecond, worker and counter, but not necessarily the same string as Encode draws new random
+// symbols unless the generator is sortable; compare Ids with Shortid.Compare. Converting back
+// values that were not converted from short Ids fails with ErrOverflow if their time, worker or
+// counter do not fit the generator.
+package convert
+
+import (
+	"errors"
+	"fmt"
+	"github.com/teris-io/shortid"
+	"time"
+)
+
+// ErrOverflow is returned when a value does not fit into the target format.
+var ErrOverflow = errors.New("value does not fit")
+
+// Bits of the worker and the counter in UUIDv7 and ULID values, which fit any generator layout.
+const (
+	workerBits  = 16
+	counterBits = 32
+)
+
+// decode decodes the Id into its Unix millisecond, worker and counter.
+func decode(sid *shortid.Shortid, id string) (int64, uint, uint, error) {
+	decoded, err := sid.Decode(id)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	// count from the epoch in whole milliseconds, the inverse of encode also for sub-ms epochs
+	unixMs := sid.Epoch().UnixMilli() + int64(decoded.Ms)
+	if unixMs < 0 {
+		return 0, 0, 0, fmt.Errorf("%w: time %v precedes the Unix epoch", ErrOverflow, decoded.Time)
+	}
+	return unixMs, decoded.Worker, decoded.Counter, nil
+}
+
+// encode composes the Id of the Unix millisecond, worker and counter.
+func encode(sid *shortid.Shortid, unixMs int64, worker, counter uint) (string, error) {
+	// compare in milliseconds, nanoseconds since 1970 overflow int64 after 2262
+	epochMs := sid.Epoch().UnixMilli()
+	if unixMs < epochMs {
+		return "", fmt.Errorf("%w: time %v precedes the epoch %v", ErrOverflow, time.UnixMilli(unixMs).UTC(), sid.Epoch())
+	}
+	ms := unixMs - epochMs
+	if lifespan := int64(sid.Layout().Lifespan() / time.Millisecond); ms >= lifespan {
+		return "", fmt.Errorf("%w: time %v ms after the epoch exceeds the lifespan of %v ms", ErrOverflow, ms, lifespan)
+	}
+	id, err := sid.Encode(shortid.DecodedID{Ms: uint(ms), Worker: worker, Counter: counter})
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrOverflow, err)
+	}
+	return id, nil
+}
+
+// checkWorkerAndCounter verifies that the worker and the counter fit the UUIDv7 and ULID layout.
+func checkWorkerAndCounter(worker, counter uint) error {
+	if worker >= 1<<workerBits {
+		return fmt.Errorf("%w: worker %v exceeds %v bits", ErrOverflow, worker, workerBits)
+	}
+	if uint64(counter) >= 1<<counterBits {
+		return fmt.Errorf("%w: counter %v exceeds %v bits", ErrOverflow, counter, counterBits)
+	}
+	return nil
+}
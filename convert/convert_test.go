@@ -0,0 +1,250 @@
+// Copyright (c) 2016-2017. Oleg Sklyar & teris.io. All rights reserved.
+// See the LICENSE file in the project root for licensing information.
+
+package convert_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"github.com/teris-io/shortid"
+	"github.com/teris-io/shortid/convert"
+	"github.com/teris-io/shortid/shortidtest"
+	"testing"
+	"time"
+)
+
+func newShortid(t *testing.T, opts ...shortid.Option) (*shortid.Shortid, *shortidtest.Clock) {
+	clock := shortidtest.NewClock(time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC))
+	sid, err := shortid.NewWithOptions(append([]shortid.Option{shortid.WithClock(clock), shortid.WithWorker(21)}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sid, clock
+}
+
+func TestUUIDv7_roundTrip(t *testing.T) {
+	sid, clock := newShortid(t)
+	var last convert.UUID
+	for i, id := range sid.MustGenerateN(300) {
+		u, err := convert.ToUUIDv7(sid, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if u[6]>>4 != 7 || u[8]>>6 != 2 {
+			t.Errorf("expected version 7 and variant 10, found %v", u)
+		}
+		if i > 0 && bytes.Compare(last[:], u[:]) >= 0 {
+			t.Errorf("expected %v before %v", last, u)
+		}
+		last = u
+		parsed, err := convert.ParseUUID(u.String())
+		if err != nil || parsed != u {
+			t.Errorf("expected %v, found %v, %v", u, parsed, err)
+		}
+		back, err := convert.FromUUIDv7(sid, u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c, err := sid.Compare(id, back); err != nil || c != 0 {
+			t.Errorf("expected %v, found %v, %v", id, back, err)
+		}
+	}
+	unixMs := int64(binary.BigEndian.Uint64(append([]byte{0, 0}, last[:6]...)))
+	if unixMs != clock.Now().UnixNano()/int64(time.Millisecond) {
+		t.Errorf("expected timestamp %v, found %v", clock.Now(), unixMs)
+	}
+}
+
+func TestUUIDv7_onRandomBitsSet_errOverflow(t *testing.T) {
+	sid, _ := newShortid(t)
+	u, err := convert.ToUUIDv7(sid, sid.MustGenerate())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, i := range []int{12, 13, 15} {
+		modified := u
+		modified[i] |= 0x01
+		if _, err := convert.FromUUIDv7(sid, modified); !errors.Is(err, convert.ErrOverflow) {
+			t.Errorf("byte %v: expected ErrOverflow, found %v", i, err)
+		}
+	}
+	// a random UUIDv7 of the same millisecond
+	random, err := convert.ParseUUID("016f5e66-e800-7a4b-9c3d-5e6f70819aab")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := convert.FromUUIDv7(sid, random); !errors.Is(err, convert.ErrOverflow) {
+		t.Errorf("expected ErrOverflow, found %v", err)
+	}
+}
+
+func TestUUIDv7_onNotVersion7_error(t *testing.T) {
+	sid, _ := newShortid(t)
+	u, err := convert.ParseUUID("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := convert.FromUUIDv7(sid, u); err == nil {
+		t.Error("expected error")
+	}
+	if _, err := convert.ParseUUID("6ba7b810-9dad-11d1-80b4"); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestULID_roundTrip(t *testing.T) {
+	sid, _ := newShortid(t, shortid.WithSortable())
+	for _, id := range sid.MustGenerateN(300) {
+		u, err := convert.ToULID(sid, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		parsed, err := convert.ParseULID(u.String())
+		if err != nil || parsed != u {
+			t.Errorf("expected %v, found %v, %v", u, parsed, err)
+		}
+		back, err := convert.FromULID(sid, u)
+		if err != nil || back != id {
+			t.Errorf("expected %v, found %v, %v", id, back, err)
+		}
+	}
+}
+
+func TestULID_onRandomBitsSet_errOverflow(t *testing.T) {
+	sid, _ := newShortid(t)
+	u, err := convert.ToULID(sid, sid.MustGenerate())
+	if err != nil {
+		t.Fatal(err)
+	}
+	u[15] = 0xff
+	if _, err := convert.FromULID(sid, u); !errors.Is(err, convert.ErrOverflow) {
+		t.Errorf("expected ErrOverflow, found %v", err)
+	}
+	// a random ULID of the same millisecond
+	random, err := convert.ParseULID("01DXF6DT00CX9QNNW7PNXQ3YR8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := convert.FromULID(sid, random); !errors.Is(err, convert.ErrOverflow) {
+		t.Errorf("expected ErrOverflow, found %v", err)
+	}
+}
+
+func TestULID_beyondYear2262_errOverflow(t *testing.T) {
+	sid, _ := newShortid(t)
+	// 20046744073710 ms since 1970 overflow int64 nanoseconds
+	var ms [8]byte
+	binary.BigEndian.PutUint64(ms[:], 20046744073710)
+	var u convert.ULID
+	copy(u[0:6], ms[2:])
+	if _, err := convert.FromULID(sid, u); !errors.Is(err, convert.ErrOverflow) {
+		t.Errorf("expected ErrOverflow, found %v", err)
+	}
+}
+
+func TestULID_canonicalForm(t *testing.T) {
+	const s = "01ARZ3NDEKTSV4RRFFQ69G5FAV"
+	u, err := convert.ParseULID("01arz3ndektsv4rrffq69g5fav")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.String() != s {
+		t.Errorf("expected %v, found %v", s, u)
+	}
+	for _, invalid := range []string{"81ARZ3NDEKTSV4RRFFQ69G5FAV", "01ARZ3NDEKTSV4RRFFQ69G5FA", "01ARZ3NDEKTSV4RRFFQ69G5FAU"} {
+		if _, err := convert.ParseULID(invalid); err == nil {
+			t.Errorf("%v: expected error", invalid)
+		}
+	}
+}
+
+func TestSnowflake_roundTrip(t *testing.T) {
+	sid, clock := newShortid(t)
+	for _, id := range sid.MustGenerateN(300) {
+		snowflake, err := convert.ToSnowflake(sid, id, convert.TwitterEpoch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ms := snowflake >> 22; ms != int64(clock.Now().Sub(convert.TwitterEpoch)/time.Millisecond) {
+			t.Errorf("expected %v ms since the Twitter epoch, found %v", clock.Now().Sub(convert.TwitterEpoch), ms)
+		}
+		if machine := snowflake >> 12 & 0x3ff; machine != 21 {
+			t.Errorf("expected machine 21, found %v", machine)
+		}
+		back, err := convert.FromSnowflake(sid, snowflake, convert.TwitterEpoch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c, err := sid.Compare(id, back); err != nil || c != 0 {
+			t.Errorf("expected %v, found %v, %v", id, back, err)
+		}
+	}
+}
+
+func TestConvert_onSubMillisecondEpoch_roundTrip(t *testing.T) {
+	epoch := time.Date(2016, time.January, 1, 0, 0, 0, 500000, time.UTC)
+	sid, _ := newShortid(t, shortid.WithEpoch(epoch))
+	for _, id := range sid.MustGenerateN(3) {
+		decoded, err := sid.Decode(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var backs []string
+		u, err := convert.ToUUIDv7(sid, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		back, err := convert.FromUUIDv7(sid, u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		backs = append(backs, back)
+		l, err := convert.ToULID(sid, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if back, err = convert.FromULID(sid, l); err != nil {
+			t.Fatal(err)
+		}
+		backs = append(backs, back)
+		snowflake, err := convert.ToSnowflake(sid, id, convert.TwitterEpoch.Add(250*time.Microsecond))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if back, err = convert.FromSnowflake(sid, snowflake, convert.TwitterEpoch.Add(250*time.Microsecond)); err != nil {
+			t.Fatal(err)
+		}
+		backs = append(backs, back)
+		for _, back := range backs {
+			found, err := sid.Decode(back)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if found.Ms != decoded.Ms || found.Worker != decoded.Worker || found.Counter != decoded.Counter {
+				t.Errorf("expected %+v, found %+v", decoded, found)
+			}
+		}
+	}
+}
+
+func TestSnowflake_onOverflow_error(t *testing.T) {
+	wide, _ := newShortid(t, shortid.WithWorkerSymbols(3), shortid.WithWorker(2000))
+	if _, err := convert.ToSnowflake(wide, wide.MustGenerate(), convert.TwitterEpoch); !errors.Is(err, convert.ErrOverflow) {
+		t.Errorf("expected ErrOverflow for worker, found %v", err)
+	}
+	sid, _ := newShortid(t)
+	ids := sid.MustGenerateN(5000)
+	if _, err := convert.ToSnowflake(sid, ids[4999], convert.TwitterEpoch); !errors.Is(err, convert.ErrOverflow) {
+		t.Errorf("expected ErrOverflow for counter, found %v", err)
+	}
+	if _, err := convert.ToSnowflake(sid, ids[0], time.Now().Add(time.Hour)); !errors.Is(err, convert.ErrOverflow) {
+		t.Errorf("expected ErrOverflow for time, found %v", err)
+	}
+	if _, err := convert.FromSnowflake(sid, 500<<12, time.Now()); !errors.Is(err, convert.ErrOverflow) {
+		t.Errorf("expected ErrOverflow for machine, found %v", err)
+	}
+	if _, err := convert.FromSnowflake(sid, 0, convert.TwitterEpoch); !errors.Is(err, convert.ErrOverflow) {
+		t.Errorf("expected ErrOverflow for time before the epoch, found %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2016-2017. Oleg Sklyar & teris.io. All rights reserved.
+// See the LICENSE file in the project root for licensing information.
+
+package convert
+
+import (
+	"fmt"
+	"github.com/teris-io/shortid"
+	"time"
+)
+
+// TwitterEpoch is the epoch of Twitter Snowflake Ids, 2010-11-04 01:42:54.657 UTC.
+var TwitterEpoch = time.Unix(0, 1288834974657*int64(time.Millisecond)).UTC()
+
+// Bits of the fields of a Snowflake Id following the sign bit.
+const (
+	snowflakeTimeBits     = 41
+	snowflakeMachineBits  = 10
+	snowflakeSequenceBits = 12
+)
+
+// ToSnowflake converts the Id into a Snowflake Id with the given epoch, e.g. TwitterEpoch: 41 bits
+// of milliseconds since the epoch, the worker as the 10-bit machine Id and the counter as the
+// 12-bit sequence number. Workers beyond 1023 and counters beyond 4095 do not fit.
+func ToSnowflake(sid *shortid.Shortid, id string, epoch time.Time) (int64, error) {
+	decoded, err := sid.Decode(id)
+	if err != nil {
+		return 0, err
+	}
+	delta := sid.Epoch().UnixMilli() + int64(decoded.Ms) - epoch.UnixMilli()
+	if delta < 0 {
+		return 0, fmt.Errorf("%w: time %v precedes the Snowflake epoch %v", ErrOverflow, decoded.Time, epoch)
+	}
+	ms := uint64(delta)
+	if ms >= 1<<snowflakeTimeBits {
+		return 0, fmt.Errorf("%w: time exceeds %v bits", ErrOverflow, snowflakeTimeBits)
+	}
+	if decoded.Worker >= 1<<snowflakeMachineBits {
+		return 0, fmt.Errorf("%w: worker %v exceeds %v bits", ErrOverflow, decoded.Worker, snowflakeMachineBits)
+	}
+	if decoded.Counter >= 1<<snowflakeSequenceBits {
+		return 0, fmt.Errorf("%w: counter %v exceeds %v bits", ErrOverflow, decoded.Counter, snowflakeSequenceBits)
+	}
+	return int64(ms<<(snowflakeMachineBits+snowflakeSequenceBits) |
+		uint64(decoded.Worker)<<snowflakeSequenceBits | uint64(decoded.Counter)), nil
+}
+
+// FromSnowflake converts a Snowflake Id with the given epoch into the Id with the same time, the
+// machine Id as the worker and the sequence number as the counter. Machine Ids beyond the worker
+// range of the generator do not fit.
+func FromSnowflake(sid *shortid.Shortid, snowflake int64, epoch time.Time) (string, error) {
+	if snowflake < 0 {
+		return "", fmt.Errorf("invalid negative Snowflake Id %v", snowflake)
+	}
+	ms := snowflake >> (snowflakeMachineBits + snowflakeSequenceBits)
+	machine := uint(snowflake>>snowflakeSequenceBits) & (1<<snowflakeMachineBits - 1)
+	sequence := uint(snowflake) & (1<<snowflakeSequenceBits - 1)
+	unixMs := epoch.UnixMilli() + ms
+	return encode(sid, unixMs, machine, sequence)
+}
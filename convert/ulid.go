@@ -0,0 +1,88 @@
+// Copyright (c) 2016-2017. Oleg Sklyar & teris.io. All rights reserved.
+// See the LICENSE file in the project root for licensing information.
+
+package convert
+
+import (
+	"encoding/binary"
+	"fmt"
+	"github.com/teris-io/shortid"
+	"strings"
+)
+
+// ULID is a ULID in its binary form, convertible to and from the [16]byte ULID types of other
+// packages.
+type ULID [16]byte
+
+// crockford is the ULID alphabet.
+const crockford = shortid.Crockford32ABC
+
+// String returns the ULID in its canonical form of 26 Crockford base32 symbols.
+func (u ULID) String() string {
+	hi, lo := binary.BigEndian.Uint64(u[:8]), binary.BigEndian.Uint64(u[8:])
+	var buf [26]byte
+	for i := 25; i >= 0; i-- {
+		buf[i] = crockford[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(buf[:])
+}
+
+// ParseULID parses a ULID in its canonical form, case-insensitively.
+func ParseULID(s string) (ULID, error) {
+	var u ULID
+	if len(s) != 26 || s[0] > '7' {
+		return u, fmt.Errorf("invalid ULID '%v'", s)
+	}
+	var hi, lo uint64
+	for i := 0; i < len(s); i++ {
+		v := strings.IndexByte(crockford, upper(s[i]))
+		if v < 0 {
+			return u, fmt.Errorf("invalid ULID '%v': symbol '%c' at position %v", s, s[i], i)
+		}
+		hi = hi<<5 | lo>>59
+		lo = lo<<5 | uint64(v)
+	}
+	binary.BigEndian.PutUint64(u[:8], hi)
+	binary.BigEndian.PutUint64(u[8:], lo)
+	return u, nil
+}
+
+func upper(c byte) byte {
+	if 'a' <= c && c <= 'z' {
+		return c - 'a' + 'A'
+	}
+	return c
+}
+
+// ToULID converts the Id into a ULID with the Id time as its 48-bit Unix millisecond timestamp,
+// followed in the 80 bits of randomness by 16 bits of worker, 32 bits of counter and zeros. ULIDs
+// of the same worker thus order as the Ids.
+func ToULID(sid *shortid.Shortid, id string) (ULID, error) {
+	var u ULID
+	unixMs, worker, counter, err := decode(sid, id)
+	if err != nil {
+		return u, err
+	}
+	if unixMs >= 1<<48 {
+		return u, fmt.Errorf("%w: time exceeds 48 bits", ErrOverflow)
+	}
+	if err := checkWorkerAndCounter(worker, counter); err != nil {
+		return u, err
+	}
+	putUint48(u[0:6], uint64(unixMs))
+	binary.BigEndian.PutUint16(u[6:8], uint16(worker))
+	binary.BigEndian.PutUint32(u[8:12], uint32(counter))
+	return u, nil
+}
+
+// FromULID converts a ULID produced by ToULID back into the Id.
+func FromULID(sid *shortid.Shortid, u ULID) (string, error) {
+	if u[12]|u[13]|u[14]|u[15] != 0 {
+		return "", fmt.Errorf("%w: ULID %v sets bits beyond the worker and the counter", ErrOverflow, u)
+	}
+	worker := uint(binary.BigEndian.Uint16(u[6:8]))
+	counter := uint(binary.BigEndian.Uint32(u[8:12]))
+	return encode(sid, int64(uint48(u[0:6])), worker, counter)
+}
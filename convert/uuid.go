@@ -0,0 +1,97 @@
+// Copyright (c) 2016-2017. Oleg Sklyar & teris.io. All rights reserved.
+// See the LICENSE file in the project root for licensing information.
+
+package convert
+
+import (
+	"encoding/hex"
+	"fmt"
+	"github.com/teris-io/shortid"
+)
+
+// UUID is an RFC 9562 UUID, convertible to and from the [16]byte UUID types of other packages.
+type UUID [16]byte
+
+// String returns the UUID in the canonical form xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx.
+func (u UUID) String() string {
+	var buf [36]byte
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+	return string(buf[:])
+}
+
+// ParseUUID parses a UUID in the canonical form.
+func ParseUUID(s string) (UUID, error) {
+	var u UUID
+	if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return u, fmt.Errorf("invalid UUID '%v'", s)
+	}
+	src := []byte(s[0:8] + s[9:13] + s[14:18] + s[19:23] + s[24:])
+	if _, err := hex.Decode(u[:], src); err != nil {
+		return u, fmt.Errorf("invalid UUID '%v': %v", s, err)
+	}
+	return u, nil
+}
+
+// ToUUIDv7 converts the Id into a UUIDv7 with the Id time as its 48-bit Unix millisecond
+// timestamp, followed in the 74 remaining bits (rand_a and rand_b) by 16 bits of worker, 32 bits
+// of counter and zeros. UUIDv7 values of the same worker thus order as the Ids.
+func ToUUIDv7(sid *shortid.Shortid, id string) (UUID, error) {
+	var u UUID
+	unixMs, worker, counter, err := decode(sid, id)
+	if err != nil {
+		return u, err
+	}
+	if unixMs >= 1<<48 {
+		return u, fmt.Errorf("%w: time exceeds 48 bits", ErrOverflow)
+	}
+	if err := checkWorkerAndCounter(worker, counter); err != nil {
+		return u, err
+	}
+	putUint48(u[0:6], uint64(unixMs))
+	// rand_a: version 7 and the high 12 bits of the worker
+	u[6] = 0x70 | byte(worker>>12)
+	u[7] = byte(worker >> 4)
+	// rand_b: variant 10, the low 4 bits of the worker and the counter
+	u[8] = 0x80 | byte(worker&0x0f)<<2 | byte(counter>>30)
+	u[9] = byte(counter >> 22)
+	u[10] = byte(counter >> 14)
+	u[11] = byte(counter >> 6)
+	u[12] = byte(counter << 2)
+	return u, nil
+}
+
+// FromUUIDv7 converts a UUIDv7 produced by ToUUIDv7 back into the Id.
+func FromUUIDv7(sid *shortid.Shortid, u UUID) (string, error) {
+	if u[6]>>4 != 7 || u[8]>>6 != 2 {
+		return "", fmt.Errorf("%v is not a UUIDv7", u)
+	}
+	if u[12]&0x03|u[13]|u[14]|u[15] != 0 {
+		return "", fmt.Errorf("%w: UUID %v sets bits beyond the worker and the counter", ErrOverflow, u)
+	}
+	worker := uint(u[6]&0x0f)<<12 | uint(u[7])<<4 | uint(u[8]>>2)&0x0f
+	counter := uint(u[8]&0x03)<<30 | uint(u[9])<<22 | uint(u[10])<<14 | uint(u[11])<<6 | uint(u[12]>>2)
+	return encode(sid, int64(uint48(u[0:6])), worker, counter)
+}
+
+func putUint48(b []byte, v uint64) {
+	for i := 5; i >= 0; i-- {
+		b[i] = byte(v)
+		v >>= 8
+	}
+}
+
+func uint48(b []byte) uint64 {
+	var v uint64
+	for _, c := range b[:6] {
+		v = v<<8 | uint64(c)
+	}
+	return v
+}
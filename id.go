@@ -0,0 +1,156 @@
+// Copyright (c) 2016-2017. Oleg Sklyar & teris.io. All rights reserved.
+// See the LICENSE file in the project root for licensing information.
+
+package shortid
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"unsafe"
+)
+
+// ID is a short Id usable as a typed field of API and database models: it marshals to and from
+// text, JSON and SQL, validating on the way in that it consists of at least as many symbols of the
+// ID alphabet (see SetIDAbc) as the shortest Id of that alphabet. The empty ID represents the
+// absence of an Id and maps to JSON and SQL null.
+type ID string
+
+var idAbc *Abc
+
+// SetIDAbc sets the alphabet against which ID values are validated. With nil, the default, ID
+// values are validated against the alphabet of the default generator, see GetDefault.
+func SetIDAbc(abc *Abc) {
+	target := (*unsafe.Pointer)(unsafe.Pointer(&idAbc))
+	atomic.StorePointer(target, unsafe.Pointer(abc))
+}
+
+// IDAbc returns the alphabet against which ID values are validated.
+func IDAbc() Abc {
+	if abc := (*Abc)(atomic.LoadPointer((*unsafe.Pointer)(unsafe.Pointer(&idAbc)))); abc != nil {
+		return *abc
+	}
+	return GetDefault().Abc()
+}
+
+// ParseID validates the Id against the ID alphabet, see SetIDAbc.
+func ParseID(s string) (ID, error) {
+	abc := IDAbc()
+	return abc.ParseID(s)
+}
+
+// ParseID validates the Id against the alphabet: the Id must consist of the alphabet symbols and be
+// at least as long as the shortest Id of the alphabet. The empty string parses into the empty ID.
+func (abc *Abc) ParseID(s string) (ID, error) {
+	if s == "" {
+		return "", nil
+	}
+	n := 0
+	for i, r := range []rune(s) {
+		if _, ok := abc.indexOf(r); !ok {
+			return "", invalid(s, ErrInvalidSymbol, "symbol '%c' at position %v is not in the alphabet", r, i)
+		}
+		n++
+	}
+	if minLen := newLayout(abc.bits, 1).Len(); n < minLen {
+		return "", invalid(s, ErrInvalidLength, "expected at least %v symbols, found %v", minLen, n)
+	}
+	return ID(s), nil
+}
+
+// String returns the Id as string.
+func (id ID) String() string {
+	return string(id)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (id ID) MarshalText() ([]byte, error) {
+	return []byte(id), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler validating the Id, see ParseID.
+func (id *ID) UnmarshalText(text []byte) error {
+	parsed, err := ParseID(string(text))
+	if err != nil {
+		return err
+	}
+	*id = parsed
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, the empty ID marshals to null.
+func (id ID) MarshalJSON() ([]byte, error) {
+	if id == "" {
+		return []byte("null"), nil
+	}
+	return json.Marshal(string(id))
+}
+
+// UnmarshalJSON implements json.Unmarshaler validating the Id, see ParseID; null unmarshals to the
+// empty ID.
+func (id *ID) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*id = ""
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	return id.UnmarshalText([]byte(s))
+}
+
+// Value implements driver.Valuer, the empty ID is stored as null.
+func (id ID) Value() (driver.Value, error) {
+	if id == "" {
+		return nil, nil
+	}
+	return string(id), nil
+}
+
+// Scan implements sql.Scanner validating the Id, see ParseID; null scans to the empty ID.
+func (id *ID) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*id = ""
+		return nil
+	case string:
+		return id.UnmarshalText([]byte(v))
+	case []byte:
+		return id.UnmarshalText(v)
+	default:
+		return fmt.Errorf("cannot scan %T into shortid.ID", src)
+	}
+}
+
+// Format implements fmt.Formatter: the verbs s, v, q, x and X format the Id as a string with the
+// given flags, width and precision, %#v yields Go syntax.
+func (id ID) Format(f fmt.State, verb rune) {
+	if verb == 'v' && f.Flag('#') {
+		fmt.Fprintf(f, "shortid.ID(%q)", string(id))
+		return
+	}
+	switch verb {
+	case 's', 'v', 'q', 'x', 'X':
+		var format strings.Builder
+		format.WriteByte('%')
+		for _, flag := range "+-# 0" {
+			if f.Flag(int(flag)) {
+				format.WriteRune(flag)
+			}
+		}
+		if width, ok := f.Width(); ok {
+			format.WriteString(strconv.Itoa(width))
+		}
+		if prec, ok := f.Precision(); ok {
+			format.WriteString("." + strconv.Itoa(prec))
+		}
+		format.WriteRune(verb)
+		fmt.Fprintf(f, format.String(), string(id))
+	default:
+		fmt.Fprintf(f, "%%!%c(shortid.ID=%s)", verb, string(id))
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2016-2017. Oleg Sklyar & teris.io. All rights reserved.
+// See the LICENSE file in the project root for licensing information.
+
+package shortid_test
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/teris-io/shortid"
+	"testing"
+)
+
+type model struct {
+	ID     shortid.ID `json:"id"`
+	Parent shortid.ID `json:"parent"`
+}
+
+func TestID_jsonRoundTrip(t *testing.T) {
+	id := shortid.ID(shortid.MustGenerate())
+	data, err := json.Marshal(model{ID: id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := fmt.Sprintf(`{"id":"%v","parent":null}`, id); string(data) != expected {
+		t.Errorf("expected %v, found %v", expected, string(data))
+	}
+	var m model
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatal(err)
+	}
+	if m.ID != id || m.Parent != "" {
+		t.Errorf("expected %v and empty parent, found %v and %v", id, m.ID, m.Parent)
+	}
+}
+
+func TestID_onUnmarshalJSON_invalid_error(t *testing.T) {
+	var m model
+	err := json.Unmarshal([]byte(`{"id":"abc.def*ghi"}`), &m)
+	if !errors.Is(err, shortid.ErrInvalidSymbol) {
+		t.Errorf("expected ErrInvalidSymbol, found %v", err)
+	}
+	err = json.Unmarshal([]byte(`{"id":"abc"}`), &m)
+	if !errors.Is(err, shortid.ErrInvalidLength) {
+		t.Errorf("expected ErrInvalidLength, found %v", err)
+	}
+}
+
+func TestID_textRoundTrip(t *testing.T) {
+	id := shortid.ID(shortid.MustGenerate())
+	text, err := id.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var parsed shortid.ID
+	if err := parsed.UnmarshalText(text); err != nil {
+		t.Fatal(err)
+	}
+	if parsed != id {
+		t.Errorf("expected %v, found %v", id, parsed)
+	}
+}
+
+func TestID_sqlRoundTrip(t *testing.T) {
+	id := shortid.ID(shortid.MustGenerate())
+	value, err := id.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, src := range []interface{}{value, []byte(value.(string))} {
+		var scanned shortid.ID
+		if err := scanned.Scan(src); err != nil {
+			t.Fatal(err)
+		}
+		if scanned != id {
+			t.Errorf("expected %v, found %v", id, scanned)
+		}
+	}
+	var _ driver.Valuer = id
+}
+
+func TestID_onNull_empty(t *testing.T) {
+	if value, err := shortid.ID("").Value(); value != nil || err != nil {
+		t.Errorf("expected nil value, found %v, %v", value, err)
+	}
+	id := shortid.ID("x")
+	if err := id.Scan(nil); err != nil || id != "" {
+		t.Errorf("expected empty Id, found %v, %v", id, err)
+	}
+	if err := id.Scan(42); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestID_onSetIDAbc_validatesAgainstAbc(t *testing.T) {
+	abc := shortid.MustNewAbc(shortid.Crockford32ABC, 1)
+	shortid.SetIDAbc(&abc)
+	defer shortid.SetIDAbc(nil)
+	if _, err := shortid.ParseID("0123456789A"); err != nil {
+		t.Error(err)
+	}
+	if _, err := shortid.ParseID("0123456789ab"); !errors.Is(err, shortid.ErrInvalidSymbol) {
+		t.Errorf("expected ErrInvalidSymbol, found %v", err)
+	}
+	if _, err := shortid.ParseID("0123456789"); !errors.Is(err, shortid.ErrInvalidLength) {
+		t.Errorf("expected ErrInvalidLength, found %v", err)
+	}
+}
+
+func TestID_format(t *testing.T) {
+	id := shortid.ID("ggWP3JLZg")
+	for format, expected := range map[string]string{
+		"%v":      "ggWP3JLZg",
+		"%s":      "ggWP3JLZg",
+		"%q":      `"ggWP3JLZg"`,
+		"%12s":    "   ggWP3JLZg",
+		"%-4.3s|": "ggW |",
+		"%x":      "67675750334a4c5a67",
+		"%#v":     `shortid.ID("ggWP3JLZg")`,
+	} {
+		if found := fmt.Sprintf(format, id); found != expected {
+			t.Errorf("%v: expected %v, found %v", format, expected, found)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2016-2017. Oleg Sklyar & teris.io. All rights reserved.
+// See the LICENSE file in the project root for licensing information.
+
+package shortid
+
+import (
+	"fmt"
+	"time"
+)
+
+// timeBits is the minimum number of bits encoding the ms since epoch, 2^40 ms being 34 years.
+const timeBits = 40
+
+// workerBits is the number of bits encoding the worker unless the worker symbols are configured.
+const workerBits = 5
+
+// maxWorkerSymbols limits the number of worker symbols that can be configured.
+const maxWorkerSymbols = 4
+
+// maxIDLen is the largest number of symbols of the time and worker fields over all layouts.
+const maxIDLen = 14 + maxWorkerSymbols
+
+// Layout describes how the millisecond since epoch, the worker and the counter are encoded over the
+// symbols of an Id. It is derived from the alphabet size 2^n (or the largest power of two not
+// exceeding it): the time and worker symbols carry n-1 bits of data each, choosing randomly between
+// at least 2 matching symbols, the counter symbols carry n bits each with no randomness. For the
+// default 64-symbol alphabet these are 8 time symbols and 1 worker symbol, for 32 to 63 symbols
+// 10 and 2, and for 16 to 31 symbols (e.g. hex) 14 and 2. The worker field encodes workers
+// [0,31] unless its width is configured with WithWorkerSymbols.
+type Layout struct {
+	TimeSymbols   int  // number of symbols encoding the ms since epoch
+	WorkerSymbols int  // number of symbols encoding the worker
+	Digits        uint // bits of data per time and worker symbol
+	CounterDigits uint // bits of data per counter symbol
+	workerBits    uint // bits of the worker field in use
+}
+
+// newLayout computes the layout for the alphabet size 2^abcBits with the given number of worker
+// symbols, or the number required for workers [0,31] if 0.
+func newLayout(abcBits uint, workerSymbols int) Layout {
+	digits := abcBits - 1
+	l := Layout{
+		TimeSymbols:   int((timeBits + digits - 1) / digits),
+		WorkerSymbols: int((workerBits + digits - 1) / digits),
+		Digits:        digits,
+		CounterDigits: abcBits,
+		workerBits:    workerBits,
+	}
+	if workerSymbols > 0 {
+		l.WorkerSymbols = workerSymbols
+		l.workerBits = uint(workerSymbols) * digits
+	}
+	return l
+}
+
+// Len returns the length of Ids generated at a rate of up to 1 Id per millisecond; Ids generated
+// within the same millisecond are extended by the counter.
+func (l Layout) Len() int {
+	return l.TimeSymbols + l.WorkerSymbols
+}
+
+// Lifespan returns the period after the epoch during which the time symbols can accommodate the
+// ms since epoch.
+func (l Layout) Lifespan() time.Duration {
+	return (1 << l.timeBits()) * time.Millisecond
+}
+
+// MaxWorker returns the largest worker number that can be encoded in the worker symbols.
+func (l Layout) MaxWorker() uint {
+	return 1<<l.workerBits - 1
+}
+
+// String returns a string representation of the layout.
+func (l Layout) String() string {
+	return fmt.Sprintf("Layout(len=%v, time=%vx%v bits, worker=%vx%v bits, counter=%v bits, lifespan=%v, workers=%v)",
+		l.Len(), l.TimeSymbols, l.Digits, l.WorkerSymbols, l.Digits, l.CounterDigits, l.Lifespan(), l.MaxWorker()+1)
+}
+
+func (l Layout) timeBits() uint {
+	return uint(l.TimeSymbols) * l.Digits
+}
+
+// Layout returns the layout of Ids generated by this generator.
+func (sid *Shortid) Layout() Layout {
+	return sid.layout
+}
+
+// WithWorkerSymbols sets the number of symbols [1,4] encoding the worker, extending the worker
+// range beyond [0,31] at the cost of longer Ids. For the default alphabet every worker symbol
+// carries 5 bits, thus 2 symbols encode 1024 workers and yield Ids of 10 symbols. By default the
+// worker is encoded in as few symbols as required for 32 workers (a single symbol for the default
+// alphabet), which is compatible with earlier versions. Fewer symbols than the default would
+// shrink the range, so the generator rejects them: alphabets of 16 to 63 symbols need at least 2.
+func WithWorkerSymbols(n int) Option {
+	return func(opts *options) error {
+		if n < 1 || maxWorkerSymbols < n {
+			return fmt.Errorf("expected worker symbols in the range [1,%v]", maxWorkerSymbols)
+		}
+		opts.wsymbols = n
+		return nil
+	}
+}
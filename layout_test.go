@@ -0,0 +1,148 @@
+// Copyright (c) 2016-2017. Oleg Sklyar & teris.io. All rights reserved.
+// See the LICENSE file in the project root for licensing information.
+
+package shortid_test
+
+import (
+	"errors"
+	"github.com/teris-io/shortid"
+	"github.com/teris-io/shortid/shortidtest"
+	"testing"
+	"time"
+)
+
+func alphabetOfSize(size int) string {
+	runes := make([]rune, size)
+	for i := range runes {
+		runes[i] = rune(0x4e00 + i)
+	}
+	return string(runes)
+}
+
+func TestShortid_onLayout_perAlphabetSize(t *testing.T) {
+	tests := []struct {
+		alphabet string
+		len      int
+		lifespan time.Duration
+	}{
+		{"0123456789abcdef", 16, (1 << 42) * time.Millisecond},
+		{"0123456789abcdefghjkmnpqrstvwxyz", 12, (1 << 40) * time.Millisecond},
+		{shortid.DefaultABC, 9, (1 << 40) * time.Millisecond},
+		{alphabetOfSize(128), 8, (1 << 42) * time.Millisecond},
+		{alphabetOfSize(256), 7, (1 << 42) * time.Millisecond},
+	}
+	for _, test := range tests {
+		sid, err := shortid.NewWithOptions(shortid.WithAlphabet(test.alphabet), shortid.WithWorker(31))
+		if err != nil {
+			t.Fatal(err)
+		}
+		layout := sid.Layout()
+		if layout.Len() != test.len || layout.Lifespan() != test.lifespan {
+			t.Errorf("expected len %v and lifespan %v, found %v", test.len, test.lifespan, layout)
+		}
+		if !sid.ExpiresAt().Equal(sid.Epoch().Add(test.lifespan)) {
+			t.Errorf("expected expiry after %v, found %v", test.lifespan, sid.ExpiresAt())
+		}
+	}
+}
+
+func TestShortid_onGenerate_perAlphabetSize_decodable(t *testing.T) {
+	alphabets := []string{"0123456789abcdef", "0123456789abcdefghjkmnpqrstvwxyz", shortid.DefaultABC,
+		alphabetOfSize(128), alphabetOfSize(256)}
+	for _, alphabet := range alphabets {
+		clock := shortidtest.NewClock(time.Date(2049, time.January, 1, 0, 0, 0, 0, time.UTC))
+		sid, err := shortid.NewWithOptions(shortid.WithAlphabet(alphabet), shortid.WithWorker(21), shortid.WithClock(clock))
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids := sid.MustGenerateN(5000)
+		unique := make(map[string]struct{}, len(ids))
+		for i, id := range ids {
+			unique[id] = struct{}{}
+			decoded, err := sid.Decode(id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !decoded.Time.Equal(clock.Now()) || decoded.Worker != 21 || decoded.Counter != uint(i) {
+				t.Errorf("expected %v, worker 21 and counter %v, found %+v", clock.Now(), i, decoded)
+			}
+		}
+		if len(unique) != len(ids) {
+			t.Errorf("expected %v unique ids, found %v", len(ids), len(unique))
+		}
+		if n := len([]rune(ids[0])); n != sid.Layout().Len() {
+			t.Errorf("expected first id of length %v, found %v", sid.Layout().Len(), n)
+		}
+	}
+}
+
+func TestAbc_onNewAbc_sizeOutOfRange_error(t *testing.T) {
+	for _, size := range []int{8, 15, 257, 512} {
+		if _, err := shortid.NewAbc(alphabetOfSize(size), 1); err == nil {
+			t.Errorf("expected error for size %v", size)
+		}
+	}
+}
+
+func TestAbc_onEncode_hexAlphabet_digitsRange(t *testing.T) {
+	abc := shortid.MustNewAbc("0123456789abcdef", 1)
+	for digits := uint(0); digits < 8; digits++ {
+		_, err := abc.Encode(25, 0, digits)
+		if valid := 2 <= digits && digits <= 4; valid && err != nil {
+			t.Error(err)
+		} else if !valid && err == nil {
+			t.Errorf("expected error for digits %v", digits)
+		}
+	}
+}
+
+func TestShortid_withWorkerSymbols_extendsWorkerRange(t *testing.T) {
+	sid, err := shortid.NewWithOptions(shortid.WithWorkerSymbols(2), shortid.WithWorker(1000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sid.Layout().Len() != 10 || sid.Layout().MaxWorker() != 1023 {
+		t.Errorf("expected Ids of 10 symbols for 1024 workers, found %v", sid.Layout())
+	}
+	for _, id := range sid.MustGenerateN(100) {
+		if decoded, err := sid.Decode(id); err != nil {
+			t.Error(err)
+		} else if decoded.Worker != 1000 {
+			t.Errorf("expected worker 1000, found %v", decoded.Worker)
+		}
+	}
+	if _, err := shortid.NewWithOptions(shortid.WithWorkerSymbols(2), shortid.WithWorker(1024)); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestShortid_withWorkerSymbols_outOfRange_error(t *testing.T) {
+	for _, n := range []int{-1, 0, 5} {
+		if _, err := shortid.NewWithOptions(shortid.WithWorkerSymbols(n)); err == nil {
+			t.Errorf("expected error for %v", n)
+		}
+	}
+	for _, alphabet := range []string{shortid.Crockford32ABC, "0123456789abcdef"} {
+		if _, err := shortid.NewWithOptions(shortid.WithAlphabet(alphabet), shortid.WithWorkerSymbols(1)); err == nil {
+			t.Errorf("%v: expected error for fewer worker symbols than the default", alphabet)
+		}
+	}
+}
+
+func TestShortid_onValidate_workerOutOfRange_error(t *testing.T) {
+	wide, err := shortid.NewWithOptions(shortid.WithAlphabet(shortid.Crockford32ABC), shortid.WithWorker(200),
+		shortid.WithWorkerSymbols(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sid, err := shortid.NewWithOptions(shortid.WithAlphabet(shortid.Crockford32ABC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sid.Layout().MaxWorker() != 31 || sid.Layout().Len() != wide.Layout().Len() {
+		t.Fatalf("expected 32 workers within the same length, found %v", sid.Layout())
+	}
+	if err := sid.Validate(wide.MustGenerate()); !errors.Is(err, shortid.ErrInvalidWorker) {
+		t.Errorf("expected ErrInvalidWorker, found %v", err)
+	}
+}
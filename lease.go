@@ -0,0 +1,69 @@
+// Copyright (c) 2016-2017. Oleg Sklyar & teris.io. All rights reserved.
+// See the LICENSE file in the project root for licensing information.
+
+package shortid
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// WorkerLeaser assigns worker numbers to generators so that generators coordinated by the same
+// leaser never share a worker number. The worker package provides implementations backed by lock
+// files, an in-process registry and a database/sql database.
+type WorkerLeaser interface {
+	// Acquire leases a free worker number, blocking at most until the context is done.
+	Acquire(ctx context.Context) (uint8, Lease, error)
+}
+
+// Lease represents a leased worker number.
+type Lease interface {
+	// Lost returns a channel that is closed once the lease is lost, e.g. if it could not be renewed
+	// before expiry, after which the worker number may be leased to another generator.
+	Lost() <-chan struct{}
+	// Release returns the worker number to the leaser.
+	Release() error
+}
+
+// ErrLeaseLost is returned by Generate once the lease of the worker number is lost: the generator
+// stops issuing Ids rather than risk duplicates with the next holder of the worker number.
+var ErrLeaseLost = errors.New("worker lease lost")
+
+// WithWorkerLeaser makes the constructor acquire the worker number from the given leaser within the
+// given context instead of using WithWorker. The lease is held until Close is called on the
+// generator; once it is lost, Generate returns ErrLeaseLost.
+func WithWorkerLeaser(ctx context.Context, leaser WorkerLeaser) Option {
+	return func(opts *options) error {
+		if leaser == nil {
+			return errors.New("expected non-nil worker leaser")
+		}
+		opts.leaseCtx = ctx
+		opts.leaser = leaser
+		return nil
+	}
+}
+
+func (sid *Shortid) acquire(ctx context.Context, leaser WorkerLeaser) error {
+	worker, lease, err := leaser.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	if maxWorker := sid.layout.MaxWorker(); uint(worker) > maxWorker {
+		_ = lease.Release()
+		return fmt.Errorf("expected leased worker in the range [0,%v], found %v", maxWorker, worker)
+	}
+	sid.worker = uint(worker)
+	sid.lease = lease
+	sid.lost = lease.Lost()
+	return nil
+}
+
+func (sid *Shortid) checkLease() error {
+	select {
+	case <-sid.lost:
+		return ErrLeaseLost
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2016-2017. Oleg Sklyar & teris.io. All rights reserved.
+// See the LICENSE file in the project root for licensing information.
+
+package shortid_test
+
+import (
+	"context"
+	"errors"
+	"github.com/teris-io/shortid"
+	"testing"
+)
+
+type fixedLeaser struct {
+	worker   uint8
+	lost     chan struct{}
+	released bool
+}
+
+func (l *fixedLeaser) Acquire(context.Context) (uint8, shortid.Lease, error) {
+	l.lost = make(chan struct{})
+	return l.worker, l, nil
+}
+
+func (l *fixedLeaser) Lost() <-chan struct{} {
+	return l.lost
+}
+
+func (l *fixedLeaser) Release() error {
+	l.released = true
+	return nil
+}
+
+func TestShortid_onWorkerLeaser_leasedWorkerUsed(t *testing.T) {
+	leaser := &fixedLeaser{worker: 17}
+	sid, err := shortid.NewWithOptions(shortid.WithWorker(3), shortid.WithWorkerLeaser(context.Background(), leaser))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sid.Worker() != 17 {
+		t.Errorf("expected leased worker 17, found %v", sid.Worker())
+	}
+	id, err := sid.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded := must(sid.Decode(id)); decoded.Worker != 17 {
+		t.Errorf("expected worker 17 in id, found %v", decoded.Worker)
+	}
+	if err := sid.Close(); err != nil || !leaser.released {
+		t.Errorf("expected lease released on close, found %v", err)
+	}
+}
+
+func TestShortid_onWorkerLeaser_leaseLost_error(t *testing.T) {
+	leaser := &fixedLeaser{worker: 1}
+	sid, err := shortid.NewWithOptions(shortid.WithWorkerLeaser(context.Background(), leaser))
+	if err != nil {
+		t.Fatal(err)
+	}
+	close(leaser.lost)
+	if _, err := sid.Generate(); !errors.Is(err, shortid.ErrLeaseLost) {
+		t.Errorf("expected ErrLeaseLost from Generate, found %v", err)
+	}
+	if _, err := sid.GenerateN(3); !errors.Is(err, shortid.ErrLeaseLost) {
+		t.Errorf("expected ErrLeaseLost from GenerateN, found %v", err)
+	}
+}
+
+func TestShortid_onWorkerLeaser_workerOutOfRange_error(t *testing.T) {
+	leaser := &fixedLeaser{worker: 200}
+	if _, err := shortid.NewWithOptions(shortid.WithWorkerLeaser(context.Background(), leaser)); err == nil {
+		t.Error("expected error")
+	}
+	if !leaser.released {
+		t.Error("expected lease released")
+	}
+}
+
+func TestShortid_onWorkerLeaser_nil_error(t *testing.T) {
+	if _, err := shortid.NewWithOptions(shortid.WithWorkerLeaser(context.Background(), nil)); err == nil {
+		t.Error("expected error")
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2016-2017. Oleg Sklyar & teris.io. All rights reserved.
+// See the LICENSE file in the project root for licensing information.
+
+package shortid
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// ErrEpochExhausted is returned by Generate once the millisecond since epoch can no longer be
+// encoded in an Id, that is at or after ExpiresAt.
+var ErrEpochExhausted = errors.New("epoch exhausted, the millisecond since epoch cannot be encoded")
+
+// WithExhaustionWarning registers a function that is called once, on the first Id generated within
+// the given period before the generator's life span is over (see Layout.Lifespan). The function
+// receives the time at which the life span ends and is called synchronously from Generate, thus it
+// should not block.
+func WithExhaustionWarning(before time.Duration, warn func(expiresAt time.Time)) Option {
+	return func(opts *options) error {
+		if before < 0 {
+			return errors.New("expected non-negative warning period")
+		}
+		opts.warnIn = before
+		opts.warn = warn
+		return nil
+	}
+}
+
+// ExpiresAt returns the time at which the life span of the generator is over and Generate starts
+// returning ErrEpochExhausted (34 years and 305 days since the epoch for 64- and 32-symbol
+// alphabets, see Layout.Lifespan).
+func (sid *Shortid) ExpiresAt() time.Time {
+	return sid.epoch.Add(sid.layout.Lifespan())
+}
+
+// RemainingLifetime returns the time left until the life span of the generator is over; the value
+// is negative if it is already over.
+func (sid *Shortid) RemainingLifetime() time.Duration {
+	return sid.ExpiresAt().Sub(sid.clock.Now())
+}
+
+func (sid *Shortid) checkExhaustion(now time.Time) {
+	if sid.warn == nil || now.Before(sid.warnAt) {
+		return
+	}
+	if atomic.CompareAndSwapUint32(&sid.warned, 0, 1) {
+		sid.warn(sid.ExpiresAt())
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2016-2017. Oleg Sklyar & teris.io. All rights reserved.
+// See the LICENSE file in the project root for licensing information.
+
+package shortid_test
+
+import (
+	"errors"
+	"github.com/teris-io/shortid"
+	"testing"
+	"time"
+)
+
+func TestShortid_onExpiresAt_success(t *testing.T) {
+	sid := shortid.MustNew(0, shortid.DefaultABC, 1)
+	expected := time.Date(2050, time.November, 3, 19, 53, 47, 776000000, time.UTC)
+	if !sid.ExpiresAt().Equal(expected) {
+		t.Errorf("expected %v, found %v", expected, sid.ExpiresAt())
+	}
+}
+
+func TestShortid_onRemainingLifetime_success(t *testing.T) {
+	sid := shortid.MustNew(0, shortid.DefaultABC, 1)
+	upper := time.Until(sid.ExpiresAt())
+	remaining := sid.RemainingLifetime()
+	if lower := time.Until(sid.ExpiresAt()); remaining < lower || upper < remaining {
+		t.Errorf("expected between %v and %v, found %v", lower, upper, remaining)
+	}
+}
+
+func TestShortid_onGenerate_pastExpiry_errEpochExhausted(t *testing.T) {
+	sid := shortid.MustNew(0, shortid.DefaultABC, 1)
+	tm := sid.ExpiresAt()
+	if _, err := sid.GenerateInternal(&tm, sid.Epoch()); !errors.Is(err, shortid.ErrEpochExhausted) {
+		t.Errorf("expected ErrEpochExhausted, found %v", err)
+	}
+	tm = tm.Add(-time.Millisecond)
+	if _, err := sid.GenerateInternal(&tm, sid.Epoch()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestShortid_onGenerate_beforeEpoch_error(t *testing.T) {
+	sid := shortid.MustNew(0, shortid.DefaultABC, 1)
+	tm := sid.Epoch().Add(-time.Millisecond)
+	if _, err := sid.GenerateInternal(&tm, sid.Epoch()); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestShortid_onGenerate_withinWarningPeriod_warnsOnce(t *testing.T) {
+	ref := shortid.MustNew(0, shortid.DefaultABC, 1)
+	lifespan := ref.ExpiresAt().Sub(ref.Epoch())
+	epoch := time.Now().Add(time.Hour - lifespan)
+	var warnings []time.Time
+	sid, err := shortid.NewWithOptions(
+		shortid.WithEpoch(epoch),
+		shortid.WithExhaustionWarning(2*time.Hour, func(expiresAt time.Time) {
+			warnings = append(warnings, expiresAt)
+		}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := sid.Generate(); err != nil {
+			t.Error(err)
+		}
+	}
+	if len(warnings) != 1 {
+		t.Fatalf("expected exactly 1 warning, found %v", len(warnings))
+	}
+	if !warnings[0].Equal(sid.ExpiresAt()) {
+		t.Errorf("expected %v, found %v", sid.ExpiresAt(), warnings[0])
+	}
+}
+
+func TestShortid_onGenerate_beforeWarningPeriod_noWarning(t *testing.T) {
+	sid, err := shortid.NewWithOptions(
+		shortid.WithExhaustionWarning(time.Hour, func(time.Time) {
+			t.Error("unexpected warning")
+		}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sid.MustGenerate()
+}
+
+func TestShortid_onNewWithOptions_withWarningPeriodNegative_error(t *testing.T) {
+	if _, err := shortid.NewWithOptions(shortid.WithExhaustionWarning(-time.Hour, func(time.Time) {})); err == nil {
+		t.Error("expected error")
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright (c) 2016-2017. Oleg Sklyar & teris.io. All rights reserved.
+// See the LICENSE file in the project root for licensing information.
+
+package shortid
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
+
+// DefaultEpoch is the beginning of millisecond counting used unless configured otherwise.
+var DefaultEpoch = time.Date(2016, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// Option configures the short Id generator constructed with NewWithOptions.
+type Option func(*options) error
+
+type options struct {
+	worker   uint
+	alphabet string
+	seed     uint64
+	epoch    time.Time
+	epochSet bool
+	warnIn   time.Duration
+	warn     func(expiresAt time.Time)
+	policy   ClockPolicy
+	clock    Clock
+	random   io.Reader
+	strict   bool
+	fold     bool
+	block    blocklist
+	wsymbols int
+	leaseCtx context.Context
+	leaser   WorkerLeaser
+	store    StateStore
+	interval time.Duration
+	margin   time.Duration
+	sortable bool
+}
+
+// WithWorker sets the worker number [0,31] (default: 0), or up to Layout().MaxWorker() if the
+// worker range is extended with WithWorkerSymbols. The worker number should be different for
+// multiple or distributed processes generating Ids into the same data space.
+func WithWorker(worker uint) Option {
+	return func(opts *options) error {
+		opts.worker = worker
+		return nil
+	}
+}
+
+// WithAlphabet sets the alphabet of 16 to 256 unique symbols (default: DefaultABC), e.g. one of
+// the presets such as NoLookalikesABC. The alphabet size determines the Layout of the Ids: their
+// length and life span.
+func WithAlphabet(alphabet string) Option {
+	return func(opts *options) error {
+		opts.alphabet = alphabet
+		return nil
+	}
+}
+
+// WithSeed sets the seed value used to shuffle the alphabet (default: 1). The seed should be
+// identical for all workers generating Ids into the same data space.
+func WithSeed(seed uint64) Option {
+	return func(opts *options) error {
+		opts.seed = seed
+		return nil
+	}
+}
+
+// WithEpoch sets the beginning of millisecond counting (default: DefaultEpoch). Ids can be
+// generated for the life span of the Layout since this date (34 years for the default alphabet),
+// thus the epoch should be close to the date when the generation of Ids starts. The epoch must not
+// be in the future.
+func WithEpoch(epoch time.Time) Option {
+	return func(opts *options) error {
+		opts.epoch = epoch
+		opts.epochSet = true
+		return nil
+	}
+}
+
+// WithEntropy sets the source of randomness used to pick between matching symbols (default:
+// crypto/rand). A deterministic source combined with a controllable clock yields reproducible Ids,
+// see the shortidtest package.
+func WithEntropy(random io.Reader) Option {
+	return func(opts *options) error {
+		opts.random = random
+		return nil
+	}
+}
+
+// WithStrictEntropy makes Generate return ErrEntropy if the source of randomness fails instead of
+// silently falling back to the predictable math/rand.
+func WithStrictEntropy() Option {
+	return func(opts *options) error {
+		opts.strict = true
+		return nil
+	}
+}
+
+// NewWithOptions constructs an instance of the short Id generator configured by the given
+// options. Without options it is equivalent to New(0, DefaultABC, 1).
+func NewWithOptions(opts ...Option) (*Shortid, error) {
+	o := options{
+		worker:   0,
+		alphabet: DefaultABC,
+		seed:     1,
+		epoch:    DefaultEpoch,
+		clock:    SystemClock{},
+	}
+	for _, opt := range opts {
+		if err := opt(&o); err != nil {
+			return nil, err
+		}
+	}
+	newAbc := NewAbc
+	if o.fold {
+		newAbc = NewCaseInsensitiveAbc
+	}
+	abc, err := newAbc(o.alphabet, o.seed)
+	if err != nil {
+		return nil, err
+	}
+	if o.sortable {
+		abc = abc.sorted()
+	}
+	abc = abc.WithEntropy(o.random, o.strict)
+	layout := newLayout(abc.bits, o.wsymbols)
+	if min := newLayout(abc.bits, 0).WorkerSymbols; layout.WorkerSymbols < min {
+		return nil, fmt.Errorf("expected at least %v worker symbols for an alphabet of %v symbols", min, len(abc.alphabet))
+	}
+	if o.worker > layout.MaxWorker() {
+		return nil, fmt.Errorf("expected worker in the range [0,%v]", layout.MaxWorker())
+	}
+	// running out of the life span is left to Generate, so that New cannot fail on time
+	if o.epochSet && o.epoch.After(o.clock.Now()) {
+		return nil, fmt.Errorf("epoch %v is in the future", o.epoch)
+	}
+	var block *blocklist
+	if !o.block.empty() {
+		block = &o.block
+	}
+	sid := &Shortid{
+		abc:    abc,
+		layout: layout,
+		worker: o.worker,
+		epoch:  o.epoch,
+		warnAt: o.epoch.Add(layout.Lifespan() - o.warnIn),
+		warn:   o.warn,
+		block:  block,
+		policy: o.policy,
+		clock:  o.clock,
+		sorted: o.sortable,
+	}
+	if o.store != nil {
+		if err := sid.restore(o.store, o.interval, o.margin); err != nil {
+			return nil, err
+		}
+	}
+	if o.leaser != nil {
+		if err := sid.acquire(o.leaseCtx, o.leaser); err != nil {
+			return nil, err
+		}
+	}
+	if sid.ckpt != nil {
+		sid.startCheckpoints(o.interval)
+	}
+	return sid, nil
+}
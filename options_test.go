@@ -0,0 +1,84 @@
+// Copyright (c) 2016-2017. Oleg Sklyar & teris.io. All rights reserved.
+// See the LICENSE file in the project root for licensing information.
+
+package shortid_test
+
+import (
+	"errors"
+	"github.com/teris-io/shortid"
+	"github.com/teris-io/shortid/shortidtest"
+	"testing"
+	"time"
+)
+
+func TestShortid_onNewWithOptions_defaults_equivalentToNew(t *testing.T) {
+	sid, err := shortid.NewWithOptions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := shortid.MustNew(0, shortid.DefaultABC, 1).String(); sid.String() != expected {
+		t.Errorf("expected %v, found %v", expected, sid.String())
+	}
+}
+
+func TestShortid_onNewWithOptions_success(t *testing.T) {
+	epoch := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	sid, err := shortid.NewWithOptions(
+		shortid.WithWorker(3),
+		shortid.WithAlphabet(shortid.DefaultABC),
+		shortid.WithSeed(2),
+		shortid.WithEpoch(epoch))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "Shortid(worker=3, epoch=2024-03-01 00:00:00 +0000 UTC, abc=Abc{alphabet='ip8bKduCDxnMQy-JrVHAN5h1s396jBvmFZOL0Pg2WTqwIE7f4ackXzoUSYlGt_eR'))"
+	if sid.String() != expected {
+		t.Errorf("expected %v, found %v", expected, sid.String())
+	}
+	id := sid.MustGenerate()
+	if decoded, err := sid.Decode(id); err != nil {
+		t.Error(err)
+	} else if time.Since(decoded.Time) > time.Second {
+		t.Errorf("expected time close to now, found %v", decoded.Time)
+	}
+}
+
+func TestShortid_onNewWithOptions_withWorkerAbove31_error(t *testing.T) {
+	if _, err := shortid.NewWithOptions(shortid.WithWorker(32)); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestShortid_onNewWithOptions_withIncorrectAbc_error(t *testing.T) {
+	if _, err := shortid.NewWithOptions(shortid.WithAlphabet("aasefvowefvjaHEFV")); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestShortid_onNewWithOptions_withEpochInFuture_error(t *testing.T) {
+	if _, err := shortid.NewWithOptions(shortid.WithEpoch(time.Now().Add(time.Hour))); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestShortid_onNewWithOptions_afterLifespan_noErrorButGenerateFails(t *testing.T) {
+	clock := shortidtest.NewClock(shortid.DefaultEpoch.Add(50 * 365 * 24 * time.Hour))
+	sid, err := shortid.NewWithOptions(shortid.WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sid.Generate(); !errors.Is(err, shortid.ErrEpochExhausted) {
+		t.Errorf("expected ErrEpochExhausted, found %v", err)
+	}
+}
+
+func TestShortid_onNewWithOptions_withExhaustedEpoch_reportsDate(t *testing.T) {
+	epoch := time.Date(1970, time.January, 1, 0, 0, 0, 0, time.UTC)
+	sid, err := shortid.NewWithOptions(shortid.WithEpoch(epoch))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expires := sid.ExpiresAt().Format("2006-01-02"); expires != "2004-11-03" {
+		t.Errorf("expected exhaustion on 2004-11-03, found %v", expires)
+	}
+}
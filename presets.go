@@ -0,0 +1,27 @@
+// Copyright (c) 2016-2017. Oleg Sklyar & teris.io. All rights reserved.
+// See the LICENSE file in the project root for licensing information.
+
+package shortid
+
+// Alphabet presets for use with New, NewAbc and WithAlphabet. The Id length and life span are
+// given for Ids generated at a rate of up to 1 Id per millisecond, see Layout.
+const (
+	// URLSafeABC consists of the 64 unreserved URL characters (RFC 3986) excluding '.' and '~'
+	// and is identical to DefaultABC: Ids of 9 symbols, 34-year life span.
+	URLSafeABC = DefaultABC
+
+	// NoLookalikesABC consists of 56 digits and letters excluding the easily confused 0/O/o,
+	// 1/l/I and the '_' and '-' symbols, e.g. for support codes read out over the phone: Ids of
+	// 12 symbols, 34-year life span.
+	NoLookalikesABC = "23456789abcdefghijkmnpqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ"
+
+	// Base58ABC is the 58-symbol Bitcoin alphabet excluding 0/O and I/l: Ids of 12 symbols,
+	// 34-year life span.
+	Base58ABC = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+	// Crockford32ABC is the 32-symbol Crockford base32 alphabet of digits and upper-case letters
+	// excluding I, L, O and U: Ids of 12 symbols, 34-year life span. Crockford base32 decoders
+	// accept lower-case input, for which the generator can be made case-insensitive with
+	// WithCaseInsensitive.
+	Crockford32ABC = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+)
@@ -0,0 +1,84 @@
+// Copyright (c) 2016-2017. Oleg Sklyar & teris.io. All rights reserved.
+// See the LICENSE file in the project root for licensing information.
+
+package shortid_test
+
+import (
+	"github.com/teris-io/shortid"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAbc_onNewAbc_presets_success(t *testing.T) {
+	presets := map[string]int{
+		shortid.URLSafeABC:      9,
+		shortid.NoLookalikesABC: 12,
+		shortid.Base58ABC:       12,
+		shortid.Crockford32ABC:  12,
+	}
+	for preset, length := range presets {
+		if _, err := shortid.NewAbc(preset, 1); err != nil {
+			t.Error(err)
+		}
+		sid, err := shortid.New(3, preset, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sid.Layout().Len() != length {
+			t.Errorf("expected length %v for %v, found %v", length, preset, sid.Layout())
+		}
+		if sid.Layout().Lifespan() != (1<<40)*time.Millisecond {
+			t.Errorf("expected 34-year life span for %v, found %v", preset, sid.Layout())
+		}
+	}
+}
+
+func TestAbc_onNoLookalikesABC_excludesAmbiguousSymbols(t *testing.T) {
+	if strings.ContainsAny(shortid.NoLookalikesABC, "0Oo1lI_-") {
+		t.Errorf("expected no ambiguous symbols in %v", shortid.NoLookalikesABC)
+	}
+}
+
+func TestShortid_onGenerate_withNonPowerOfTwoAlphabet_decodable(t *testing.T) {
+	sid := shortid.MustNew(5, shortid.Base58ABC, 1)
+	for _, id := range sid.MustGenerateN(10000) {
+		if decoded, err := sid.Decode(id); err != nil {
+			t.Error(err)
+		} else if decoded.Worker != 5 {
+			t.Errorf("expected worker 5, found %v", decoded.Worker)
+		}
+		if err := sid.Validate(id); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestAbc_onEncode_withNonPowerOfTwoAlphabet_usesAllSymbols(t *testing.T) {
+	abc := shortid.MustNewAbc(shortid.Base58ABC, 1)
+	symbols := make(map[rune]struct{})
+	for val := uint(0); val < 10000; val++ {
+		runes := abc.MustEncode(val, 4, 4)
+		for _, r := range runes {
+			symbols[r] = struct{}{}
+		}
+		if decoded := abc.MustDecode(runes, 4); decoded != val {
+			t.Errorf("expected %v, found %v", val, decoded)
+		}
+	}
+	if len(symbols) != 58 {
+		t.Errorf("expected all 58 symbols in use, found %v", len(symbols))
+	}
+}
+
+func TestAbc_onNewAbc_nonPrintable_error(t *testing.T) {
+	runes := []rune(shortid.DefaultABC)
+	runes[10] = ' '
+	if _, err := shortid.NewAbc(string(runes), 1); err == nil {
+		t.Error("expected error")
+	}
+	runes[10] = '\n'
+	if _, err := shortid.NewAbc(string(runes), 1); err == nil {
+		t.Error("expected error")
+	}
+}
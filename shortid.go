@@ -37,29 +37,34 @@
 //
 // The package guarantees the generation of unique Ids with zero collisions for 34 years
 // (1/1/2016-1/1/2050) using the same worker Id within a single (although concurrent) application if
-// application restarts take longer than 1 millisecond. The package supports up to 32 works, all
-// providing unique sequences.
+// application restarts take longer than 1 millisecond, or regardless of restarts if the generator
+// persists its state (see WithStateStore). The package supports up to 32 workers, all providing
+// unique sequences, or more with a wider worker field (see WithWorkerSymbols). The 34 years are
+// counted from the epoch, which can be moved closer to the start of Id generation using
+// NewWithOptions and WithEpoch.
 //
 // Implementation details
 //
 // Although heavily inspired by the node.js shortid library this is
 // not a simple Go port. In addition it
 //
-//  - is safe to concurrency;
+//  - is safe to concurrency and lock-free;
 //  - does not require any yearly version/epoch resets;
 //  - provides stable Id size over a long period at the rate of 1ms;
 //  - guarantees no collisions (due to guaranteed fixed size of Ids between milliseconds and because
 //    multiple requests within the same ms lead to longer Ids with the prefix unique to the ms);
-//  - supports 32 over 16 workers.
+//  - supports 32 workers by default, more with a wider worker field.
 //
 // The algorithm uses less randomness than the original node.js implementation, which permits to
 // extend the life span as well as reduce and guarantee the length. In general terms, each Id
-// has the following 3 pieces of information encoded: the millisecond (first 8 symbols), the worker
-// Id (9th symbol), running concurrent counter within the same millisecond, only if required, over
-// all remaining symbols. The element of randomness per symbol is 1/2 for the worker and the
+// has the following 3 pieces of information encoded: the millisecond (the leading time symbols),
+// the worker Id (the worker symbols following them), running concurrent counter within the same
+// millisecond, only if required, over all remaining symbols. The number of symbols of each field
+// follows from the alphabet size, see Layout: with the default 64-symbol alphabet these are 8 time
+// symbols and 1 worker symbol. The element of randomness per symbol is 1/2 for the worker and the
 // millisecond and 0 for the counter. Here 0 means no randomness, i.e. every value is encoded using
-// a 64-base alphabet; 1/2 means one of two matching symbols of the supplied alphabet, 1/4 one of
-// four matching symbols. The original algorithm of the node.js module uses 1/4 throughout.
+// all symbols of the alphabet; 1/2 means one of two matching symbols of the supplied alphabet, 1/4
+// one of four matching symbols. The original algorithm of the node.js module uses 1/4 throughout.
 //
 // All methods accepting the parameters that govern the randomness are exported and can be used
 // to directly implement an algorithm with e.g. more randomness, but with longer Ids and shorter
@@ -70,11 +75,14 @@ import (
 	randc "crypto/rand"
 	"errors"
 	"fmt"
-	"math"
+	"io"
+	"math/bits"
 	randm "math/rand"
 	"sync"
 	"sync/atomic"
 	"time"
+	"unicode"
+	"unicode/utf8"
 	"unsafe"
 )
 
@@ -88,16 +96,31 @@ const DefaultABC = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWX
 // encode data.
 type Abc struct {
 	alphabet []rune
+	bytes    []byte       // alphabet as bytes if all symbols are single-byte, nil otherwise
+	bits     uint         // largest power of 2 not exceeding the alphabet size
+	fold     bool         // look up symbols case-insensitively
+	index    map[rune]int // position of each symbol in the shuffled alphabet
+	random   io.Reader    // source of randomness, crypto/rand if nil
+	strict   bool         // return entropy failures instead of falling back to math/rand
 }
 
 // Shortid type represents a short Id generator working with a given alphabet.
 type Shortid struct {
+	state  uint64 // ms since epoch and request count within the ms for the last id, see pack
 	abc    Abc
+	layout Layout // derived from the alphabet size
 	worker uint
-	epoch  time.Time  // ids can be generated for 34 years since this date
-	ms     uint       // ms since epoch for the last id
-	count  uint       // request count within the same ms
-	mx     sync.Mutex // locks access to ms and count
+	epoch  time.Time       // ids can be generated for 34 years since this date
+	warnAt time.Time       // time after which the exhaustion warning fires
+	warn   func(time.Time) // exhaustion warning, nil if not configured
+	warned uint32          // set atomically once the warning has fired
+	block  *blocklist      // words and patterns excluded from Ids, nil if not configured
+	lease  Lease           // lease of the worker number, nil if not leased
+	lost   <-chan struct{} // closed once the lease is lost, nil if not leased
+	ckpt   *checkpointer   // state persistence, nil if not configured
+	policy ClockPolicy     // reaction to the clock moving backwards
+	clock  Clock           // source of the current time
+	sorted bool            // K-sortable layout, see WithSortable
 }
 
 var shortid *Shortid
@@ -124,6 +147,10 @@ func Generate() (string, error) {
 	return shortid.Generate()
 }
 
+// ErrEntropy is returned by Encode, and thus by Generate, if the alphabet is configured to fail on
+// entropy errors and its source of randomness fails.
+var ErrEntropy = errors.New("entropy source failed")
+
 // MustGenerate acts just like Generate, but panics instead of returning errors.
 func MustGenerate() string {
 	id, err := Generate()
@@ -133,26 +160,14 @@ func MustGenerate() string {
 	panic(err)
 }
 
-// New constructs an instance of the short Id generator for the given worker number [0,31], alphabet
-// (64 unique symbols) and seed value (to shuffle the alphabet). The worker number should be
-// different for multiple or distributed processes generating Ids into the same data space. The
-// seed, on contrary, should be identical.
+// New constructs an instance of the short Id generator for the given worker number, alphabet (16
+// to 256 unique symbols, see NewAbc) and seed value (to shuffle the alphabet). The worker range
+// follows from the Layout of the alphabet, [0,31] for all alphabets by default. The worker number
+// should be different for multiple or distributed processes generating Ids into the same data
+// space. The seed, on contrary, should be identical. The epoch is set to DefaultEpoch, use
+// NewWithOptions to configure a different one.
 func New(worker uint8, alphabet string, seed uint64) (*Shortid, error) {
-	if worker > 31 {
-		return nil, errors.New("expected worker in the range [0,31]")
-	}
-	abc, err := NewAbc(alphabet, seed)
-	if err == nil {
-		sid := &Shortid{
-			abc:    abc,
-			worker: uint(worker),
-			epoch:  time.Date(2016, time.January, 1, 0, 0, 0, 0, time.UTC),
-			ms:     0,
-			count:  0,
-		}
-		return sid, nil
-	}
-	return nil, err
+	return NewWithOptions(WithWorker(uint(worker)), WithAlphabet(alphabet), WithSeed(seed))
 }
 
 // MustNew acts just like New, but panics instead of returning errors.
@@ -164,9 +179,14 @@ func MustNew(worker uint8, alphabet string, seed uint64) *Shortid {
 	panic(err)
 }
 
-// Generate generates a new short Id.
+// Generate generates a new short Id. It returns ErrEpochExhausted once the life span of the
+// generator (see ExpiresAt) is over.
 func (sid *Shortid) Generate() (string, error) {
-	return sid.GenerateInternal(nil, sid.epoch)
+	buf, err := sid.AppendGenerate(make([]byte, 0, 16))
+	if err != nil {
+		return "", err
+	}
+	return string(buf), nil
 }
 
 // MustGenerate acts just like Generate, but panics instead of returning errors.
@@ -178,47 +198,255 @@ func (sid *Shortid) MustGenerate() string {
 	panic(err)
 }
 
-// GenerateInternal should only be used for testing purposes.
+// GenerateInternal should only be used for testing purposes. It uses the given time (or the clock of
+// the generator if nil) and epoch and bypasses the clock regression policy of the generator.
 func (sid *Shortid) GenerateInternal(tm *time.Time, epoch time.Time) (string, error) {
-	ms, count := sid.getMsAndCounter(tm, epoch)
-	idrunes := make([]rune, 9)
-	if tmp, err := sid.abc.Encode(ms, 8, 5); err == nil {
-		copy(idrunes, tmp) // first 8 symbols
-	} else {
+	ms, count, err := sid.getMsAndCounter(tm, epoch)
+	if err != nil {
 		return "", err
 	}
-	if tmp, err := sid.abc.Encode(sid.worker, 1, 5); err == nil {
-		idrunes[8] = tmp[0]
-	} else {
+	return sid.encode(ms, count)
+}
+
+func (sid *Shortid) encode(ms, count uint) (string, error) {
+	random := make([]byte, sid.layout.Len())
+	if err := sid.abc.readRandom(random); err != nil {
+		return "", err
+	}
+	buf, ok, err := sid.appendAllowed(make([]byte, 0, 16), ms, count, random)
+	if err != nil {
 		return "", err
 	}
+	if !ok {
+		return "", ErrBlocked
+	}
+	return string(buf), nil
+}
+
+// appendID appends the Id for the given millisecond, worker and counter to dst taking the random
+// component of the millisecond and of the worker symbols from random, one byte per symbol.
+func (sid *Shortid) appendID(dst []byte, ms, worker, count uint, random []byte) []byte {
+	if sid.sorted {
+		return sid.appendSortedID(dst, ms, worker, count)
+	}
+	l := sid.layout
+	dst = sid.abc.appendEncoded(dst, ms, uint(l.TimeSymbols), l.Digits, random[:l.TimeSymbols])
+	dst = sid.abc.appendEncoded(dst, worker, uint(l.WorkerSymbols), l.Digits, random[l.TimeSymbols:])
 	if count > 0 {
-		if countrunes, err := sid.abc.Encode(count, 0, 6); err == nil {
-			// only extend if really need it
-			idrunes = append(idrunes, countrunes...)
-		} else {
+		// only extend if really need it
+		dst = sid.abc.appendEncoded(dst, count, encodedSize(count, l.CounterDigits), l.CounterDigits, nil)
+	}
+	return dst
+}
+
+// DecodedID represents the information encoded in an Id: the generation time, the worker and the
+// running counter within the millisecond.
+type DecodedID struct {
+	Time    time.Time // generation time truncated to the millisecond
+	Ms      uint      // milliseconds since epoch
+	Worker  uint      // worker number of the generator
+	Counter uint      // running counter within the millisecond, 0 for the first Id
+}
+
+// Decode recovers the generation time (relative to Epoch), the worker and the counter from an
+// Id generated by this or an equally configured generator. Ids that are too short, too long for the
+// largest counter or contain symbols outside of the alphabet result in a *ValidationError, see
+// Validate.
+func (sid *Shortid) Decode(id string) (DecodedID, error) {
+	idrunes := []rune(id)
+	for i, r := range idrunes {
+		if _, ok := sid.abc.indexOf(r); !ok {
+			return DecodedID{}, invalid(id, ErrInvalidSymbol, "symbol '%c' at position %v is not in the alphabet", r, i)
+		}
+	}
+	l := sid.layout
+	if len(idrunes) < l.Len() {
+		return DecodedID{}, invalid(id, ErrInvalidLength, "expected at least %v symbols, found %v", l.Len(), len(idrunes))
+	}
+	if maxLen := sid.maxLen(); len(idrunes) > maxLen {
+		return DecodedID{}, invalid(id, ErrInvalidLength, "expected at most %v symbols, found %v", maxLen, len(idrunes))
+	}
+	if sid.sorted {
+		return sid.decodeSorted(id, idrunes)
+	}
+	ms, err := sid.abc.Decode(idrunes[:l.TimeSymbols], l.Digits)
+	if err != nil {
+		return DecodedID{}, err
+	}
+	worker, err := sid.abc.Decode(idrunes[l.TimeSymbols:l.Len()], l.Digits)
+	if err != nil {
+		return DecodedID{}, err
+	}
+	var count uint
+	if len(idrunes) > l.Len() {
+		// counters carry no randomness, so only one spelling is valid
+		counter := idrunes[l.Len():]
+		for i, r := range counter {
+			if index, _ := sid.abc.indexOf(r); index >= 1<<l.CounterDigits {
+				return DecodedID{}, invalid(id, ErrInvalidSymbol, "symbol '%c' at position %v is not a counter symbol", r, l.Len()+i)
+			}
+		}
+		if count, err = sid.abc.Decode(counter, l.CounterDigits); err != nil {
+			return DecodedID{}, err
+		}
+		if maxCount := sid.maxCount(); count > maxCount {
+			return DecodedID{}, invalid(id, ErrInvalidLength, "counter %v exceeds the maximum of %v", count, maxCount)
+		}
+		if count == 0 || uint(len(counter)) != encodedSize(count, l.CounterDigits) {
+			return DecodedID{}, invalid(id, ErrInvalidLength, "counter %v is not encoded in %v symbols", count, len(counter))
+		}
+	}
+	return DecodedID{
+		Time:    sid.epoch.Add(time.Duration(ms) * time.Millisecond),
+		Ms:      ms,
+		Worker:  worker,
+		Counter: count,
+	}, nil
+}
+
+// Encode is the inverse of Decode: it composes the Id of the millisecond (relative to Epoch; Time
+// is ignored), the worker and the counter, e.g. to restore Ids converted to other formats. The
+// worker is not restricted to the worker of this generator, and the result is not checked against
+// the blocklist.
+func (sid *Shortid) Encode(decoded DecodedID) (string, error) {
+	if decoded.Ms >= uint(sid.layout.Lifespan()/time.Millisecond) {
+		return "", ErrEpochExhausted
+	}
+	if maxWorker := sid.layout.MaxWorker(); decoded.Worker > maxWorker {
+		return "", fmt.Errorf("expected worker in the range [0,%v], found %v", maxWorker, decoded.Worker)
+	}
+	if maxCount := sid.maxCount(); decoded.Counter > maxCount {
+		return "", fmt.Errorf("expected counter in the range [0,%v], found %v", maxCount, decoded.Counter)
+	}
+	random := make([]byte, sid.layout.Len())
+	if !sid.sorted {
+		if err := sid.abc.readRandom(random); err != nil {
 			return "", err
 		}
 	}
-	return string(idrunes), nil
+	return string(sid.appendID(make([]byte, 0, 16), decoded.Ms, decoded.Worker, decoded.Counter, random)), nil
 }
 
-func (sid *Shortid) getMsAndCounter(tm *time.Time, epoch time.Time) (uint, uint) {
-	sid.mx.Lock()
-	defer sid.mx.Unlock()
-	var ms uint
+func (sid *Shortid) getMsAndCounter(tm *time.Time, epoch time.Time) (uint, uint, error) {
+	if err := sid.checkLease(); err != nil {
+		return 0, 0, err
+	}
+	var now time.Time
 	if tm != nil {
-		ms = uint(tm.Sub(epoch).Nanoseconds() / 1000000)
+		now = *tm
 	} else {
-		ms = uint(time.Now().Sub(epoch).Nanoseconds() / 1000000)
+		now = sid.clock.Now()
 	}
-	if ms == sid.ms {
-		sid.count++
-	} else {
-		sid.count = 0
-		sid.ms = ms
+	ms, err := sid.msSince(now, epoch)
+	if err != nil {
+		return 0, 0, err
+	}
+	maxCount := sid.maxCount()
+	for {
+		state := atomic.LoadUint64(&sid.state)
+		lastMs, lastCount := sid.unpack(state)
+		count := uint(0)
+		if ms == lastMs {
+			if count = lastCount + 1; count > maxCount {
+				return 0, 0, errors.New("too many Ids within the same millisecond")
+			}
+		}
+		if atomic.CompareAndSwapUint64(&sid.state, state, sid.pack(ms, count)) {
+			return ms, count, nil
+		}
+	}
+}
+
+// reserve acts like getMsAndCounter for the current time and the epoch of the generator, but
+// reserves n consecutive counter values within the millisecond and applies the clock regression
+// policy if the clock is behind the last used millisecond. It returns the first reserved counter.
+// The state is updated by compare-and-swap, thus concurrent calls never block each other.
+func (sid *Shortid) reserve(n uint) (uint, uint, error) {
+	if err := sid.checkLease(); err != nil {
+		return 0, 0, err
+	}
+	maxCount := sid.maxCount()
+	if n > maxCount+1 {
+		return 0, 0, fmt.Errorf("cannot reserve more than %v Ids within the same millisecond", maxCount+1)
+	}
+	for {
+		state := atomic.LoadUint64(&sid.state)
+		lastMs, lastCount := sid.unpack(state)
+		now := sid.clock.Now()
+		ms, err := sid.msSince(now, sid.epoch)
+		if err != nil {
+			return 0, 0, err
+		}
+		if sid.ckpt != nil && ms < sid.ckpt.resumeAt {
+			if sid.policy != ClockWait {
+				return 0, 0, ErrNotReady
+			}
+			sid.clock.Sleep(sid.epoch.Add(time.Duration(sid.ckpt.resumeAt) * time.Millisecond).Sub(now))
+			continue
+		}
+		var first uint
+		switch {
+		case ms > lastMs:
+			first = 0
+		case ms == lastMs:
+			first = lastCount + 1
+		case sid.policy == ClockWait:
+			sid.clock.Sleep(sid.epoch.Add(time.Duration(lastMs) * time.Millisecond).Sub(now))
+			continue
+		case sid.policy == ClockFail:
+			return 0, 0, ErrClockMovedBackwards
+		default:
+			ms = lastMs
+			first = lastCount + 1
+		}
+		if first+n-1 > maxCount {
+			// the millisecond is used up, wait for the next one
+			sid.clock.Sleep(sid.epoch.Add(time.Duration(ms+1) * time.Millisecond).Sub(now))
+			continue
+		}
+		if atomic.CompareAndSwapUint64(&sid.state, state, sid.pack(ms, first+n-1)) {
+			return ms, first, nil
+		}
+	}
+}
+
+// countBits is the number of low bits of the generator state holding the request count within the
+// ms, the high bits hold the ms since epoch (40 or 42 bits depending on the layout).
+func (sid *Shortid) countBits() uint {
+	return 64 - sid.layout.timeBits()
+}
+
+// maxLen returns the length of the Id with the largest counter, including the length symbol of the
+// counter of sortable Ids.
+func (sid *Shortid) maxLen() int {
+	n := sid.layout.Len() + int(encodedSize(sid.maxCount(), sid.layout.CounterDigits))
+	if sid.sorted {
+		n++
+	}
+	return n
+}
+
+func (sid *Shortid) maxCount() uint {
+	return 1<<sid.countBits() - 1
+}
+
+func (sid *Shortid) pack(ms, count uint) uint64 {
+	return uint64(ms)<<sid.countBits() | uint64(count)
+}
+
+func (sid *Shortid) unpack(state uint64) (uint, uint) {
+	return uint(state >> sid.countBits()), uint(state) & sid.maxCount()
+}
+
+func (sid *Shortid) msSince(tm time.Time, epoch time.Time) (uint, error) {
+	elapsed := tm.Sub(epoch)
+	if elapsed < 0 {
+		return 0, fmt.Errorf("time precedes epoch %v", epoch)
 	}
-	return sid.ms, sid.count
+	if elapsed >= sid.layout.Lifespan() {
+		return 0, ErrEpochExhausted
+	}
+	return uint(elapsed.Nanoseconds() / 1000000), nil
 }
 
 // String returns a string representation of the short Id generator.
@@ -231,8 +459,8 @@ func (sid *Shortid) Abc() Abc {
 	return sid.abc
 }
 
-// Epoch returns the value of epoch used as the beginning of millisecond counting (normally
-// 2016-01-01 00:00:00 local time)
+// Epoch returns the value of epoch used as the beginning of millisecond counting (by default
+// 2016-01-01 00:00:00 UTC, see WithEpoch).
 func (sid *Shortid) Epoch() time.Time {
 	return sid.epoch
 }
@@ -242,16 +470,48 @@ func (sid *Shortid) Worker() uint {
 	return sid.worker
 }
 
-// NewAbc constructs a new instance of shuffled alphabet to be used for Id representation.
+// Close releases the resources held by the generator: it saves the final state to the state store
+// and releases the lease of the worker number, if configured. The generator must not be used
+// afterwards.
+func (sid *Shortid) Close() error {
+	var err error
+	if sid.ckpt != nil {
+		err = sid.stopCheckpoints()
+	}
+	if sid.lease != nil {
+		if lerr := sid.lease.Release(); err == nil {
+			err = lerr
+		}
+	}
+	return err
+}
+
+// minAbcBits and maxAbcBits limit the alphabet size to [16,256] symbols.
+const (
+	minAbcBits = 4
+	maxAbcBits = 8
+)
+
+// NewAbc constructs a new instance of shuffled alphabet to be used for Id representation. The
+// alphabet must consist of 16 to 256 unique printable symbols, the Layout of the Ids and thus their
+// length and life span follow from the alphabet size: for sizes that are not a power of two, the
+// data is encoded as for the next smaller power of two with the excess symbols used as random
+// alternatives, e.g. Base58ABC yields the same Layout as Crockford32ABC.
 func NewAbc(alphabet string, seed uint64) (Abc, error) {
 	runes := []rune(alphabet)
-	if len(runes) != len(DefaultABC) {
-		return Abc{}, fmt.Errorf("alphabet must contain %v unique characters", len(DefaultABC))
+	if len(runes) < 1<<minAbcBits || 1<<maxAbcBits < len(runes) {
+		return Abc{}, fmt.Errorf("alphabet must contain between %v and %v unique characters", 1<<minAbcBits, 1<<maxAbcBits)
 	}
 	if nonUnique(runes) {
 		return Abc{}, errors.New("alphabet must contain unique characters only")
 	}
-	abc := Abc{alphabet: nil}
+	for _, r := range runes {
+		if !unicode.IsPrint(r) || unicode.IsSpace(r) {
+			return Abc{}, fmt.Errorf("alphabet must contain printable characters only, found %q", r)
+		}
+	}
+	abcBits := uint(bits.Len(uint(len(runes))) - 1)
+	abc := Abc{alphabet: nil, bits: abcBits}
 	abc.shuffle(alphabet, seed)
 	return abc, nil
 }
@@ -265,6 +525,14 @@ func MustNewAbc(alphabet string, seed uint64) Abc {
 	panic(err)
 }
 
+func (abc *Abc) indexOf(r rune) (int, bool) {
+	if abc.fold {
+		r = unicode.ToLower(r)
+	}
+	index, ok := abc.index[r]
+	return index, ok
+}
+
 func nonUnique(runes []rune) bool {
 	found := make(map[rune]struct{})
 	for _, r := range runes {
@@ -284,7 +552,20 @@ func (abc *Abc) shuffle(alphabet string, seed uint64) {
 		abc.alphabet = append(abc.alphabet, source[i])
 		source = append(source[:i], source[i+1:]...)
 	}
-	abc.alphabet = append(abc.alphabet, source[0])
+	abc.setAlphabet(append(abc.alphabet, source[0]))
+}
+
+// setAlphabet sets the symbols in order of their values and indexes them.
+func (abc *Abc) setAlphabet(runes []rune) {
+	abc.alphabet = runes
+	abc.index = make(map[rune]int, len(runes))
+	for i, r := range runes {
+		abc.index[r] = i
+	}
+	abc.bytes = nil
+	if str := string(runes); len(str) == len(runes) {
+		abc.bytes = []byte(str)
+	}
 }
 
 // Encode encodes a given value into a slice of runes of length nsymbols. In case nsymbols==0, the
@@ -294,35 +575,34 @@ func (abc *Abc) shuffle(alphabet string, seed uint64) {
 // represents n in 2^n, which defines how much randomness flows into the algorithm: 4 -- every value
 // can be represented by 4 symbols in the alphabet (permitting at most 16 values), 5 -- every value
 // can be represented by 2 symbols in the alphabet (permitting at most 32 values), 6 -- every value
-// is represented by exactly 1 symbol with no randomness (permitting 64 values).
+// is represented by exactly 1 symbol with no randomness (permitting 64 values). The range shifts
+// with the alphabet size 2^n to [n-2,n], e.g. [3,5] for 32-symbol alphabets.
 func (abc *Abc) Encode(val, nsymbols, digits uint) ([]rune, error) {
-	if digits < 4 || 6 < digits {
-		return nil, fmt.Errorf("allowed digits range [4,6], found %v", digits)
+	if err := abc.checkDigits(digits); err != nil {
+		return nil, err
 	}
 
-	var computedSize uint = 1
-	if val >= 1 {
-		computedSize = uint(math.Log2(float64(val)))/digits + 1
-	}
+	computedSize := encodedSize(val, digits)
 	if nsymbols == 0 {
 		nsymbols = computedSize
 	} else if nsymbols < computedSize {
 		return nil, fmt.Errorf("cannot accommodate data, need %v digits, got %v", computedSize, nsymbols)
 	}
 
-	mask := 1<<digits - 1
+	mask := uint(1)<<digits - 1
 
-	random := make([]int, int(nsymbols))
-	// no random component if digits == 6
-	if digits < 6 {
-		copy(random, maskedRandomInts(len(random), 0x3f-mask))
+	random := make([]byte, int(nsymbols))
+	// no random component if digits == abc.bits
+	if digits < abc.bits {
+		if err := abc.readRandom(random); err != nil {
+			return nil, err
+		}
 	}
 
 	res := make([]rune, int(nsymbols))
 	for i := range res {
 		shift := digits * uint(i)
-		index := (int(val>>shift) & mask) | random[i]
-		res[i] = abc.alphabet[index]
+		res[i] = abc.alphabet[abc.symbolIndex((val>>shift)&mask, digits, random[i])]
 	}
 	return res, nil
 }
@@ -336,19 +616,116 @@ func (abc *Abc) MustEncode(val, size, digits uint) []rune {
 	panic(err)
 }
 
-func maskedRandomInts(size, mask int) []int {
-	ints := make([]int, size)
-	bytes := make([]byte, size)
-	if _, err := randc.Read(bytes); err == nil {
-		for i, b := range bytes {
-			ints[i] = int(b) & mask
+// Decode is the inverse of Encode: it strips the random component from every symbol and
+// recovers the value encoded with the given digits (see Encode for the range).
+func (abc *Abc) Decode(runes []rune, digits uint) (uint, error) {
+	if err := abc.checkDigits(digits); err != nil {
+		return 0, err
+	}
+	if uint(len(runes))*digits > bits.UintSize {
+		return 0, fmt.Errorf("cannot decode %v symbols into %v bits", len(runes), bits.UintSize)
+	}
+	mask := 1<<digits - 1
+	var val uint
+	for i, r := range runes {
+		index, ok := abc.indexOf(r)
+		if !ok {
+			return 0, fmt.Errorf("symbol '%c' is not in the alphabet", r)
 		}
-	} else {
-		for i := range ints {
-			ints[i] = randm.Intn(0xff) & mask
+		val |= uint(index&mask) << (digits * uint(i))
+	}
+	return val, nil
+}
+
+// MustDecode acts just like Decode, but panics instead of returning errors.
+func (abc *Abc) MustDecode(runes []rune, digits uint) uint {
+	res, err := abc.Decode(runes, digits)
+	if err == nil {
+		return res
+	}
+	panic(err)
+}
+
+// symbolIndex returns the index of a symbol encoding the value v < 2^digits choosing randomly
+// between all matching symbols v + k*2^digits of the alphabet. For alphabets of 2^n symbols this
+// sets the n-digits high bits of v from the random byte.
+func (abc *Abc) symbolIndex(v, digits uint, random byte) uint {
+	matching := (uint(len(abc.alphabet))-1-v)>>digits + 1
+	return v + (uint(random)>>digits)%matching<<digits
+}
+
+func (abc *Abc) checkDigits(digits uint) error {
+	if digits+2 < abc.bits || abc.bits < digits || digits == 0 {
+		return fmt.Errorf("allowed digits range [%v,%v], found %v", abc.bits-2, abc.bits, digits)
+	}
+	return nil
+}
+
+// appendEncoded appends the encoding of val over nsymbols symbols to dst taking the random component
+// of every symbol from the corresponding byte of random (none if random is nil). Digits and the
+// number of symbols must have been validated by the caller.
+func (abc *Abc) appendEncoded(dst []byte, val, nsymbols, digits uint, random []byte) []byte {
+	mask := uint(1)<<digits - 1
+	for i := uint(0); i < nsymbols; i++ {
+		index := (val >> (digits * i)) & mask
+		if random != nil {
+			index = abc.symbolIndex(index, digits, random[i])
+		}
+		if abc.bytes != nil {
+			dst = append(dst, abc.bytes[index])
+		} else {
+			dst = utf8.AppendRune(dst, abc.alphabet[index])
 		}
 	}
-	return ints
+	return dst
+}
+
+// encodedSize computes the number of symbols required to encode val using the given digits.
+func encodedSize(val, digits uint) uint {
+	if val == 0 {
+		return 1
+	}
+	return uint(bits.Len(val)-1)/digits + 1
+}
+
+// WithEntropy returns a copy of the alphabet that draws the randomness for Encode from the given
+// reader instead of crypto/rand; nil restores crypto/rand. Reads are serialized, so the reader
+// does not need to be safe for concurrent use. If strict, a failure to read from the source is
+// returned as an error from Encode; otherwise Encode silently falls back to math/rand.
+func (abc Abc) WithEntropy(random io.Reader, strict bool) Abc {
+	if random != nil {
+		random = &lockedReader{r: random}
+	}
+	abc.random = random
+	abc.strict = strict
+	return abc
+}
+
+func (abc *Abc) readRandom(buf []byte) error {
+	random := abc.random
+	if random == nil {
+		random = randc.Reader
+	}
+	if _, err := io.ReadFull(random, buf); err != nil {
+		if abc.strict {
+			return fmt.Errorf("%w: %v", ErrEntropy, err)
+		}
+		for i := range buf {
+			buf[i] = byte(randm.Intn(0xff))
+		}
+	}
+	return nil
+}
+
+type lockedReader struct {
+	r  io.Reader
+	mx sync.Mutex // serializes reads from r
+}
+
+func (lr *lockedReader) Read(buf []byte) (int, error) {
+	lr.mx.Lock()
+	defer lr.mx.Unlock()
+	return lr.r.Read(buf)
 }
 
 // String returns a string representation of the Abc instance.
@@ -0,0 +1,81 @@
+// Copyright (c) 2016-2017. Oleg Sklyar & teris.io. All rights reserved.
+// See the LICENSE file in the project root for licensing information.
+
+package shortid_test
+
+import (
+	"github.com/teris-io/shortid"
+	"testing"
+)
+
+func TestShortid_AppendGenerate_zeroAllocations(t *testing.T) {
+	sid := shortid.MustNew(0, shortid.DefaultABC, 155000)
+	buf := make([]byte, 0, 16)
+	allocs := testing.AllocsPerRun(10000, func() {
+		var err error
+		if buf, err = sid.AppendGenerate(buf[:0]); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("expected 0 allocations per Id, found %v", allocs)
+	}
+}
+
+func BenchmarkShortid_Generate(b *testing.B) {
+	sid := shortid.MustNew(0, shortid.DefaultABC, 155000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := sid.Generate(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkShortid_AppendGenerate(b *testing.B) {
+	sid := shortid.MustNew(0, shortid.DefaultABC, 155000)
+	buf := make([]byte, 0, 16)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var err error
+		if buf, err = sid.AppendGenerate(buf[:0]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkShortid_GenerateN_100(b *testing.B) {
+	sid := shortid.MustNew(0, shortid.DefaultABC, 155000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := sid.GenerateN(100); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkAbc_Encode(b *testing.B) {
+	abc := shortid.MustNewAbc(shortid.DefaultABC, 155000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := abc.Encode(uint(i), 8, 5); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkShortid_Generate_parallel measures the scaling of concurrent generation, run with
+// e.g. -cpu 1,2,4,8 to compare across GOMAXPROCS.
+func BenchmarkShortid_Generate_parallel(b *testing.B) {
+	sid := shortid.MustNew(0, shortid.DefaultABC, 155000)
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		buf := make([]byte, 0, 16)
+		for pb.Next() {
+			var err error
+			if buf, err = sid.AppendGenerate(buf[:0]); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
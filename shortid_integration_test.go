@@ -123,3 +123,37 @@ func TestShortid_Generate_500kValues_concurrently(t *testing.T) {
 		t.Errorf("expected %v unique ids, found %v", 900000, len(ids))
 	}
 }
+
+func TestShortid_GenerateN_500kValues_concurrentlyWithGenerate(t *testing.T) {
+	sid := shortid.MustNew(0, shortid.DefaultABC, 155000)
+	ids := make(map[string]struct{}, 600000)
+	var mx sync.Mutex
+	var wg sync.WaitGroup
+	for g := 0; g < 3; g++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				batch := sid.MustGenerateN(100)
+				mx.Lock()
+				for _, id := range batch {
+					ids[id] = struct{}{}
+				}
+				mx.Unlock()
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100000; i++ {
+				id := sid.MustGenerate()
+				mx.Lock()
+				ids[id] = struct{}{}
+				mx.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if len(ids) != 600000 {
+		t.Errorf("expected %v unique ids, found %v", 600000, len(ids))
+	}
+}
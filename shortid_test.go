@@ -4,6 +4,8 @@
 package shortid_test
 
 import (
+	"bytes"
+	"errors"
 	"github.com/teris-io/shortid"
 	"testing"
 	"time"
@@ -307,3 +309,171 @@ func TestAbc_onAlphabet_success(t *testing.T) {
 		t.Errorf("expected %v, found %v", expected, abc.Alphabet())
 	}
 }
+
+func TestShortid_onDecode_success(t *testing.T) {
+	sid := shortid.MustNew(7, shortid.DefaultABC, 1)
+	tm := sid.Epoch().Add(123456789 * time.Millisecond)
+	for count := uint(0); count < 100; count++ {
+		id, err := sid.GenerateInternal(&tm, sid.Epoch())
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoded, err := sid.Decode(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !decoded.Time.Equal(tm) || decoded.Ms != 123456789 {
+			t.Errorf("expected time %v, found %v (%v ms)", tm, decoded.Time, decoded.Ms)
+		}
+		if decoded.Worker != 7 {
+			t.Errorf("expected worker 7, found %v", decoded.Worker)
+		}
+		if decoded.Counter != count {
+			t.Errorf("expected counter %v, found %v", count, decoded.Counter)
+		}
+	}
+}
+
+func TestShortid_onDecode_error(t *testing.T) {
+	sid := shortid.MustNew(7, shortid.DefaultABC, 1)
+	if _, err := sid.Decode("abc"); err == nil {
+		t.Error("expected error for short id")
+	}
+	if _, err := sid.Decode("abc$efghi"); err == nil {
+		t.Error("expected error for symbol outside of alphabet")
+	}
+}
+
+func TestShortid_onEncode_roundTrip(t *testing.T) {
+	sortable, err := shortid.NewWithOptions(shortid.WithSortable())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, sid := range []*shortid.Shortid{
+		shortid.MustNew(7, shortid.DefaultABC, 1),
+		shortid.MustNew(7, shortid.Base58ABC, 1),
+		sortable,
+	} {
+		for _, decoded := range []shortid.DecodedID{
+			{Ms: 0, Worker: 0, Counter: 0},
+			{Ms: 123456789, Worker: 31, Counter: 1},
+			{Ms: 1<<40 - 1, Worker: 12, Counter: 100000},
+		} {
+			id, err := sid.Encode(decoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			found, err := sid.Decode(id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if found.Ms != decoded.Ms || found.Worker != decoded.Worker || found.Counter != decoded.Counter {
+				t.Errorf("expected %+v, found %+v", decoded, found)
+			}
+		}
+	}
+}
+
+func TestShortid_onEncode_outOfRange_error(t *testing.T) {
+	sid := shortid.MustNew(7, shortid.DefaultABC, 1)
+	for _, decoded := range []shortid.DecodedID{
+		{Ms: uint(sid.Layout().Lifespan() / time.Millisecond)},
+		{Worker: 32},
+		{Counter: 1 << 24},
+	} {
+		if _, err := sid.Encode(decoded); err == nil {
+			t.Errorf("%+v: expected error", decoded)
+		}
+	}
+}
+
+func TestAbc_onDecode_success(t *testing.T) {
+	abc := shortid.MustNewAbc(shortid.DefaultABC, 1)
+	for digits := uint(4); digits <= 6; digits++ {
+		runes := abc.MustEncode(214235345234524356, 0, digits)
+		if val, err := abc.Decode(runes, digits); err != nil {
+			t.Error(err)
+		} else if val != 214235345234524356 {
+			t.Errorf("expected 214235345234524356, found %v", val)
+		}
+	}
+}
+
+func TestAbc_onDecode_withDigitsWrong_error(t *testing.T) {
+	abc := shortid.MustNewAbc(shortid.DefaultABC, 1)
+	if _, err := abc.Decode([]rune("gz"), 3); err == nil {
+		t.Error("expected error")
+	}
+	if _, err := abc.Decode([]rune("gz"), 7); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestAbc_onMustDecode_onError_panics(t *testing.T) {
+	abc := shortid.MustNewAbc(shortid.DefaultABC, 1)
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic")
+		}
+	}()
+	abc.MustDecode([]rune("$"), 6)
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("no entropy")
+}
+
+func TestAbc_onEncode_withFailingEntropy_fallsBack(t *testing.T) {
+	abc := shortid.MustNewAbc(shortid.DefaultABC, 1).WithEntropy(failingReader{}, false)
+	if id, err := abc.Encode(25, 2, 4); err != nil {
+		t.Error(err)
+	} else if len(id) != 2 {
+		t.Errorf("expected len=2: %v", id)
+	}
+}
+
+func TestAbc_onEncode_withFailingStrictEntropy_error(t *testing.T) {
+	abc := shortid.MustNewAbc(shortid.DefaultABC, 1).WithEntropy(failingReader{}, true)
+	if _, err := abc.Encode(25, 2, 4); !errors.Is(err, shortid.ErrEntropy) {
+		t.Errorf("expected ErrEntropy, found %v", err)
+	}
+	// no randomness required
+	if _, err := abc.Encode(25, 2, 6); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestAbc_onEncode_withEntropy_deterministic(t *testing.T) {
+	abc := shortid.MustNewAbc(shortid.DefaultABC, 1)
+	first := abc.WithEntropy(bytes.NewReader([]byte{0x00, 0xff}), true)
+	if id := string(first.MustEncode(25, 2, 5)); id != "QR" {
+		t.Errorf("expected QR, found %v", id)
+	}
+}
+
+func TestShortid_onGenerate_withFailingStrictEntropy_error(t *testing.T) {
+	sid, err := shortid.NewWithOptions(shortid.WithEntropy(failingReader{}), shortid.WithStrictEntropy())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sid.Generate(); !errors.Is(err, shortid.ErrEntropy) {
+		t.Errorf("expected ErrEntropy, found %v", err)
+	}
+}
+
+func TestShortid_onGenerate_withMultiByteAlphabet_success(t *testing.T) {
+	alphabet := "0123456789abcdefghijklmnopqrstuvwxyzäöüÄÖÜßéèêàâçñABCDEFGHIJKLMN"
+	sid, err := shortid.NewWithOptions(shortid.WithAlphabet(alphabet), shortid.WithWorker(9))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range sid.MustGenerateN(100) {
+		if decoded, err := sid.Decode(id); err != nil {
+			t.Error(err)
+		} else if decoded.Worker != 9 {
+			t.Errorf("expected worker 9, found %v", decoded.Worker)
+		}
+	}
+}
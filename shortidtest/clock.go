@@ -0,0 +1,50 @@
+// Copyright (c) 2016-2017. Oleg Sklyar & teris.io. All rights reserved.
+// See the LICENSE file in the project root for licensing information.
+
+// Package shortidtest provides utilities for testing code that generates short Ids, such as a
+// controllable clock to obtain deterministic Ids and to advance time across millisecond boundaries.
+package shortidtest
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is a shortid.Clock that only moves when advanced explicitly or by Sleep. It is safe for
+// concurrent use.
+type Clock struct {
+	now time.Time
+	mx  sync.Mutex // locks access to now
+}
+
+// NewClock constructs a clock set to the given time.
+func NewClock(now time.Time) *Clock {
+	return &Clock{now: now}
+}
+
+// Now returns the current time of the clock.
+func (c *Clock) Now() time.Time {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	return c.now
+}
+
+// Sleep advances the clock by the given duration instead of blocking.
+func (c *Clock) Sleep(d time.Duration) {
+	c.Advance(d)
+}
+
+// Advance moves the clock by the given duration, which can be negative to simulate the clock
+// moving backwards.
+func (c *Clock) Advance(d time.Duration) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// Set sets the clock to the given time.
+func (c *Clock) Set(now time.Time) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	c.now = now
+}
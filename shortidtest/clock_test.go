@@ -0,0 +1,51 @@
+// Copyright (c) 2016-2017. Oleg Sklyar & teris.io. All rights reserved.
+// See the LICENSE file in the project root for licensing information.
+
+package shortidtest_test
+
+import (
+	"github.com/teris-io/shortid"
+	"github.com/teris-io/shortid/shortidtest"
+	"testing"
+	"time"
+)
+
+var _ shortid.Clock = (*shortidtest.Clock)(nil)
+
+func TestClock_onAdvance_success(t *testing.T) {
+	start := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	clock := shortidtest.NewClock(start)
+	if !clock.Now().Equal(start) {
+		t.Errorf("expected %v, found %v", start, clock.Now())
+	}
+	clock.Advance(time.Millisecond)
+	clock.Sleep(time.Second)
+	if expected := start.Add(time.Second + time.Millisecond); !clock.Now().Equal(expected) {
+		t.Errorf("expected %v, found %v", expected, clock.Now())
+	}
+	clock.Set(start)
+	if !clock.Now().Equal(start) {
+		t.Errorf("expected %v, found %v", start, clock.Now())
+	}
+}
+
+func TestClock_withShortid_deterministicMsAndCounter(t *testing.T) {
+	start := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	clock := shortidtest.NewClock(start)
+	sid, err := shortid.NewWithOptions(shortid.WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for step := 0; step < 3; step++ {
+		for count := uint(0); count < 3; count++ {
+			decoded, err := sid.Decode(sid.MustGenerate())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !decoded.Time.Equal(clock.Now()) || decoded.Counter != count {
+				t.Errorf("expected %v with counter %v, found %+v", clock.Now(), count, decoded)
+			}
+		}
+		clock.Advance(time.Millisecond)
+	}
+}
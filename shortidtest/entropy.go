@@ -0,0 +1,16 @@
+// Copyright (c) 2016-2017. Oleg Sklyar & teris.io. All rights reserved.
+// See the LICENSE file in the project root for licensing information.
+
+package shortidtest
+
+import (
+	"io"
+	"math/rand"
+)
+
+// NewEntropy constructs a deterministic source of randomness for the given seed. Generators
+// configured with equally seeded sources and clocks produce byte-identical Ids, which is useful
+// for golden-file fixtures. It must never be used in production.
+func NewEntropy(seed int64) io.Reader {
+	return rand.New(rand.NewSource(seed))
+}
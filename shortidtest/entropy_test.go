@@ -0,0 +1,38 @@
+// Copyright (c) 2016-2017. Oleg Sklyar & teris.io. All rights reserved.
+// See the LICENSE file in the project root for licensing information.
+
+package shortidtest_test
+
+import (
+	"github.com/teris-io/shortid"
+	"github.com/teris-io/shortid/shortidtest"
+	"testing"
+	"time"
+)
+
+func TestNewEntropy_withShortid_byteIdenticalIds(t *testing.T) {
+	generate := func() []string {
+		clock := shortidtest.NewClock(time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC))
+		sid, err := shortid.NewWithOptions(
+			shortid.WithClock(clock),
+			shortid.WithEntropy(shortidtest.NewEntropy(42)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []string
+		for i := 0; i < 4; i++ {
+			ids = append(ids, sid.MustGenerate(), sid.MustGenerate())
+			clock.Advance(time.Millisecond)
+		}
+		return ids
+	}
+	expected := []string{"ggWP3JLZg", "RgZE3JYZgz", "zRZPq1LWg", "kgZPq1YZgz", "igZE31YZg", "iRZPq1YZgz", "ZRZPqJLZg", "ZgWEqJLZRz"}
+	for run := 0; run < 2; run++ {
+		ids := generate()
+		for i, id := range ids {
+			if id != expected[i] {
+				t.Errorf("expected %v at %v, found %v", expected[i], i, id)
+			}
+		}
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2016-2017. Oleg Sklyar & teris.io. All rights reserved.
+// See the LICENSE file in the project root for licensing information.
+
+package shortid
+
+import (
+	"sort"
+	"time"
+	"unicode/utf8"
+)
+
+// WithSortable switches the generator to K-sortable Ids: the byte-wise order of Ids matches the
+// order of generation by (millisecond, worker, counter), so Ids can serve as clustered index keys
+// and be listed newest first without a separate timestamp column. The alphabet is sorted instead
+// of shuffled (the seed is ignored), the time and worker fields are encoded most significant symbol
+// first without random alternatives, and the counter is prefixed with its length in symbols. The
+// Layout, and thus Id length and life span, is the same as without the option.
+//
+// The trade-off: sortable Ids are sequential and expose their generation order, and Ids of the
+// same generator share their leading symbols, so they must not be used where Ids should not be
+// guessable from one another.
+func WithSortable() Option {
+	return func(opts *options) error {
+		opts.sortable = true
+		return nil
+	}
+}
+
+// Sortable reports whether the generator produces K-sortable Ids, see WithSortable.
+func (sid *Shortid) Sortable() bool {
+	return sid.sorted
+}
+
+// sorted returns a copy of the alphabet with the symbols in ascending order.
+func (abc Abc) sorted() Abc {
+	runes := append([]rune(nil), abc.alphabet...)
+	sort.Slice(runes, func(i, j int) bool { return runes[i] < runes[j] })
+	abc.setAlphabet(runes)
+	return abc
+}
+
+// appendSorted encodes the value into exactly nsymbols symbols, most significant first.
+func (abc *Abc) appendSorted(dst []byte, val, nsymbols, digits uint) []byte {
+	mask := uint(1)<<digits - 1
+	for i := nsymbols; i > 0; i-- {
+		index := (val >> (digits * (i - 1))) & mask
+		if abc.bytes != nil {
+			dst = append(dst, abc.bytes[index])
+		} else {
+			dst = utf8.AppendRune(dst, abc.alphabet[index])
+		}
+	}
+	return dst
+}
+
+// decodeSorted is the inverse of appendSorted for symbols known to be in the alphabet.
+func (abc *Abc) decodeSorted(runes []rune, digits uint) uint {
+	mask := 1<<digits - 1
+	var val uint
+	for _, r := range runes {
+		index, _ := abc.indexOf(r)
+		val = val<<digits | uint(index&mask)
+	}
+	return val
+}
+
+// appendSortedID encodes the time and the worker most significant symbol first, followed, for
+// non-zero counters, by the number of counter symbols and the counter itself.
+func (sid *Shortid) appendSortedID(dst []byte, ms, worker, count uint) []byte {
+	l := sid.layout
+	dst = sid.abc.appendSorted(dst, ms, uint(l.TimeSymbols), l.Digits)
+	dst = sid.abc.appendSorted(dst, worker, uint(l.WorkerSymbols), l.Digits)
+	if count > 0 {
+		n := encodedSize(count, l.CounterDigits)
+		dst = sid.abc.appendSorted(dst, n, 1, sid.abc.bits)
+		dst = sid.abc.appendSorted(dst, count, n, l.CounterDigits)
+	}
+	return dst
+}
+
+// decodeSorted decodes an Id of the sortable layout, see appendSortedID.
+func (sid *Shortid) decodeSorted(id string, idrunes []rune) (DecodedID, error) {
+	l := sid.layout
+	// without randomness each value has a single spelling, symbols beyond the digits alias others
+	for i, r := range idrunes {
+		limit := 1 << l.Digits
+		if i >= l.Len() {
+			limit = 1 << l.CounterDigits
+		}
+		if index, _ := sid.abc.indexOf(r); index >= limit {
+			return DecodedID{}, invalid(id, ErrInvalidSymbol, "symbol '%c' at position %v is out of range", r, i)
+		}
+	}
+	ms := sid.abc.decodeSorted(idrunes[:l.TimeSymbols], l.Digits)
+	worker := sid.abc.decodeSorted(idrunes[l.TimeSymbols:l.Len()], l.Digits)
+	var count uint
+	if counter := idrunes[l.Len():]; len(counter) > 0 {
+		n := sid.abc.decodeSorted(counter[:1], sid.abc.bits)
+		if n == 0 || uint(len(counter)-1) != n {
+			return DecodedID{}, invalid(id, ErrInvalidLength, "expected %v counter symbols, found %v", n, len(counter)-1)
+		}
+		if index, _ := sid.abc.indexOf(counter[1]); index == 0 {
+			return DecodedID{}, invalid(id, ErrInvalidLength, "counter of %v symbols has a leading zero", n)
+		}
+		count = sid.abc.decodeSorted(counter[1:], l.CounterDigits)
+		if maxCount := sid.maxCount(); count > maxCount {
+			return DecodedID{}, invalid(id, ErrInvalidLength, "counter %v exceeds the maximum of %v", count, maxCount)
+		}
+	}
+	return DecodedID{
+		Time:    sid.epoch.Add(time.Duration(ms) * time.Millisecond),
+		Ms:      ms,
+		Worker:  worker,
+		Counter: count,
+	}, nil
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2016-2017. Oleg Sklyar & teris.io. All rights reserved.
+// See the LICENSE file in the project root for licensing information.
+
+package shortid_test
+
+import (
+	"errors"
+	"github.com/teris-io/shortid"
+	"github.com/teris-io/shortid/shortidtest"
+	"sort"
+	"testing"
+	"time"
+)
+
+func newSortable(t *testing.T, clock shortid.Clock, opts ...shortid.Option) *shortid.Shortid {
+	sid, err := shortid.NewWithOptions(append([]shortid.Option{shortid.WithClock(clock), shortid.WithSortable()}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sid
+}
+
+func TestShortid_onSortable_byteOrderMatchesGenerationOrder(t *testing.T) {
+	for _, alphabet := range []string{shortid.DefaultABC, shortid.Base58ABC, shortid.Crockford32ABC, "0123456789abcdef"} {
+		clock := shortidtest.NewClock(time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC))
+		sid := newSortable(t, clock, shortid.WithAlphabet(alphabet), shortid.WithWorker(3))
+		var ids []string
+		for ms := 0; ms < 70; ms++ {
+			// up to 300 Ids per ms to cross counter length boundaries
+			for i := 0; i < ms*ms%300+1; i++ {
+				ids = append(ids, sid.MustGenerate())
+			}
+			clock.Advance(time.Duration(ms*ms+1) * time.Millisecond)
+		}
+		if !sort.StringsAreSorted(ids) {
+			t.Errorf("%v: expected Ids in generation order", alphabet)
+		}
+		for i := 1; i < len(ids); i++ {
+			if ids[i-1] == ids[i] {
+				t.Fatalf("%v: duplicate Id %v", alphabet, ids[i])
+			}
+		}
+	}
+}
+
+func TestShortid_onSortable_workersOrdered(t *testing.T) {
+	clock := shortidtest.NewClock(time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC))
+	low := newSortable(t, clock, shortid.WithWorker(1))
+	high := newSortable(t, clock, shortid.WithWorker(30))
+	first, second := high.MustGenerate(), high.MustGenerate()
+	third := low.MustGenerate()
+	if !(third < first && first < second) {
+		t.Errorf("expected %v < %v < %v", third, first, second)
+	}
+	clock.Advance(time.Millisecond)
+	if next := low.MustGenerate(); next < second {
+		t.Errorf("expected %v after %v", next, second)
+	}
+}
+
+func TestShortid_onSortable_decodeRoundTrip(t *testing.T) {
+	clock := shortidtest.NewClock(time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC))
+	sid := newSortable(t, clock, shortid.WithWorker(17))
+	ids := sid.MustGenerateN(100)
+	for i, id := range ids {
+		decoded := must(sid.Decode(id))
+		if !decoded.Time.Equal(clock.Now()) || decoded.Worker != 17 || decoded.Counter != uint(i) {
+			t.Errorf("%v: expected time %v, worker 17, counter %v, found %+v", id, clock.Now(), i, decoded)
+		}
+	}
+	if len(ids[0]) != sid.Layout().Len() {
+		t.Errorf("expected %v symbols, found %v", sid.Layout().Len(), ids[0])
+	}
+	if _, err := sid.Decode(ids[99][:len(ids[99])-1]); err == nil {
+		t.Error("expected error for truncated counter")
+	}
+}
+
+func TestShortid_onSortable_nonCanonical_error(t *testing.T) {
+	clock := shortidtest.NewClock(time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC))
+	sid := newSortable(t, clock, shortid.WithWorker(17))
+	id := sid.MustGenerate()
+	symbols := []rune(sid.Abc().Alphabet())
+	l := sid.Layout()
+
+	// time and worker symbols encode 5 bits of the 64-symbol alphabet
+	aliased := []rune(id)
+	aliased[l.TimeSymbols] = symbols[32]
+	if _, err := sid.Decode(string(aliased)); !errors.Is(err, shortid.ErrInvalidSymbol) {
+		t.Errorf("%v: expected ErrInvalidSymbol, found %v", string(aliased), err)
+	}
+
+	// counter 1 padded to 2 symbols
+	padded := id + string(symbols[2]) + string(symbols[0]) + string(symbols[1])
+	if _, err := sid.Decode(padded); !errors.Is(err, shortid.ErrInvalidLength) {
+		t.Errorf("%v: expected ErrInvalidLength, found %v", padded, err)
+	}
+	if _, err := sid.Decode(id + string(symbols[1]) + string(symbols[0])); !errors.Is(err, shortid.ErrInvalidLength) {
+		t.Errorf("expected ErrInvalidLength for zero counter, found %v", err)
+	}
+	if decoded := must(sid.Decode(id + string(symbols[1]) + string(symbols[1]))); decoded.Counter != 1 {
+		t.Errorf("expected counter 1, found %+v", decoded)
+	}
+}
+
+func TestShortid_onSortable_alphabetSorted(t *testing.T) {
+	sid := newSortable(t, shortid.SystemClock{}, shortid.WithSeed(2342))
+	abc := sid.Abc().Alphabet()
+	if !sort.SliceIsSorted([]byte(abc), func(i, j int) bool { return abc[i] < abc[j] }) {
+		t.Errorf("expected sorted alphabet, found %v", abc)
+	}
+	if !sid.Sortable() || shortid.GetDefault().Sortable() {
+		t.Error("expected only the sortable generator to report sortable")
+	}
+}
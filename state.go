@@ -0,0 +1,179 @@
+// Copyright (c) 2016-2017. Oleg Sklyar & teris.io. All rights reserved.
+// See the LICENSE file in the project root for licensing information.
+
+package shortid
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// StateStore persists the time of the last issued Id so that a restarted generator does not reuse
+// milliseconds issued before the restart, even if the restart is fast or the clock moved backwards.
+type StateStore interface {
+	// Load returns the last saved time or the zero time if nothing was saved yet.
+	Load() (time.Time, error)
+	// Save persists the time of the last issued Id.
+	Save(tm time.Time) error
+}
+
+// ErrNotReady is returned by Generate while the clock has not yet passed the time restored from the
+// state store plus the safety margin, unless the clock policy is ClockWait.
+var ErrNotReady = errors.New("clock has not passed the restored state")
+
+// FileStore is a StateStore keeping the time as Unix milliseconds in a file.
+type FileStore struct {
+	path string
+}
+
+// NewFileStore constructs a state store persisting to the file at path; the directory must exist.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// Load reads the time from the file, a missing file yields the zero time.
+func (fs *FileStore) Load() (time.Time, error) {
+	data, err := os.ReadFile(fs.path)
+	if os.IsNotExist(err) {
+		return time.Time{}, nil
+	} else if err != nil {
+		return time.Time{}, err
+	}
+	ms, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("corrupt state in %v: %v", fs.path, err)
+	}
+	return time.Unix(0, ms*int64(time.Millisecond)), nil
+}
+
+// Save replaces the file atomically with one containing the time.
+func (fs *FileStore) Save(tm time.Time) error {
+	tmp, err := os.CreateTemp(filepath.Dir(fs.path), filepath.Base(fs.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	ms := tm.UnixNano() / int64(time.Millisecond)
+	if _, err := tmp.WriteString(strconv.FormatInt(ms, 10) + "\n"); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), fs.path)
+}
+
+// WithStateStore makes the generator checkpoint the current time, or the time of the last issued
+// Id if later, to the store every interval and the time of the last issued Id on Close. On
+// construction the generator restores the time and refuses to issue Ids until the clock passes it
+// by more than interval plus margin, the interval covering Ids issued after the last checkpoint of
+// a crashed process. A non-positive interval checkpoints on Close only. The restored time does not
+// affect GenerateInternal.
+func WithStateStore(store StateStore, interval, margin time.Duration) Option {
+	return func(opts *options) error {
+		if store == nil {
+			return errors.New("expected non-nil state store")
+		}
+		if margin < 0 {
+			return fmt.Errorf("expected non-negative safety margin, found %v", margin)
+		}
+		if interval < 0 {
+			interval = 0
+		}
+		opts.store = store
+		opts.interval = interval
+		opts.margin = margin
+		return nil
+	}
+}
+
+// checkpointer saves the state of a generator to its store.
+type checkpointer struct {
+	store    StateStore
+	restored uint // ms since epoch restored from the store
+	resumeAt uint // ms since epoch from which on Ids are issued
+	stop     chan struct{}
+	done     chan struct{}
+	once     sync.Once
+}
+
+// restore loads the state from the store and computes the time from which on Ids can be issued.
+func (sid *Shortid) restore(store StateStore, interval, margin time.Duration) error {
+	tm, err := store.Load()
+	if err != nil {
+		return err
+	}
+	ckpt := &checkpointer{store: store}
+	if !tm.IsZero() && tm.After(sid.epoch) {
+		if ckpt.restored, err = sid.msSince(tm, sid.epoch); err != nil {
+			return err
+		}
+		ckpt.resumeAt = ckpt.restored + uint((interval+margin+time.Millisecond-1)/time.Millisecond) + 1
+	}
+	sid.ckpt = ckpt
+	return nil
+}
+
+// startCheckpoints launches the periodic checkpoints.
+func (sid *Shortid) startCheckpoints(interval time.Duration) {
+	ckpt := sid.ckpt
+	ckpt.stop = make(chan struct{})
+	ckpt.done = make(chan struct{})
+	go func() {
+		defer close(ckpt.done)
+		if interval == 0 {
+			<-ckpt.stop
+			return
+		}
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ckpt.stop:
+				return
+			case <-ticker.C:
+				_ = sid.checkpoint(true)
+			}
+		}
+	}()
+}
+
+// checkpoint saves the time of the last issued Id, or the restored one if none was issued since.
+// A periodic checkpoint saves the current time if later: Ids issued before the next checkpoint of
+// a process that crashes then fall within the interval a restart skips past the saved time, also
+// after the generator idled for longer than the interval.
+func (sid *Shortid) checkpoint(periodic bool) error {
+	ms, _ := sid.unpack(atomic.LoadUint64(&sid.state))
+	if ms < sid.ckpt.restored {
+		ms = sid.ckpt.restored
+	}
+	if periodic {
+		if now, err := sid.msSince(sid.clock.Now(), sid.epoch); err == nil && now > ms {
+			ms = now
+		}
+	}
+	return sid.ckpt.store.Save(sid.epoch.Add(time.Duration(ms) * time.Millisecond))
+}
+
+// stopCheckpoints stops the periodic checkpoints and saves the final state.
+func (sid *Shortid) stopCheckpoints() error {
+	var err error
+	sid.ckpt.once.Do(func() {
+		close(sid.ckpt.stop)
+		<-sid.ckpt.done
+		err = sid.checkpoint(false)
+	})
+	return err
+}
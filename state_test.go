@@ -0,0 +1,214 @@
+// Copyright (c) 2016-2017. Oleg Sklyar & teris.io. All rights reserved.
+// See the LICENSE file in the project root for licensing information.
+
+package shortid_test
+
+import (
+	"errors"
+	"github.com/teris-io/shortid"
+	"github.com/teris-io/shortid/shortidtest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFileStore_onMissingFile_zeroTime(t *testing.T) {
+	store := shortid.NewFileStore(filepath.Join(t.TempDir(), "state"))
+	tm, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !tm.IsZero() {
+		t.Errorf("expected zero time, found %v", tm)
+	}
+}
+
+func TestFileStore_roundTrip(t *testing.T) {
+	store := shortid.NewFileStore(filepath.Join(t.TempDir(), "state"))
+	saved := time.Date(2020, time.January, 1, 12, 0, 0, 123000000, time.UTC)
+	if err := store.Save(saved); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !loaded.Equal(saved) {
+		t.Errorf("expected %v, found %v", saved, loaded)
+	}
+}
+
+func TestFileStore_onCorruptFile_error(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state")
+	if err := os.WriteFile(path, []byte("garbage"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := shortid.NewFileStore(path).Load(); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestShortid_onStateStore_refusesUntilClockPassesRestoredState(t *testing.T) {
+	store := shortid.NewFileStore(filepath.Join(t.TempDir(), "state"))
+	clock := shortidtest.NewClock(time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC))
+	first, err := shortid.NewWithOptions(shortid.WithClock(clock), shortid.WithStateStore(store, time.Second, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := first.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := first.Close(); err != nil {
+		t.Fatal(err)
+	}
+	issued := must(first.Decode(id)).Time
+	if saved, err := store.Load(); err != nil || !saved.Equal(issued) {
+		t.Errorf("expected %v saved on close, found %v, %v", issued, saved, err)
+	}
+
+	// restart with the clock set back
+	clock.Set(issued.Add(-time.Minute))
+	second, err := shortid.NewWithOptions(shortid.WithClock(clock), shortid.WithStateStore(store, time.Second, 10*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer second.Close()
+	clock.Set(issued.Add(time.Second + 10*time.Millisecond))
+	if _, err := second.Generate(); !errors.Is(err, shortid.ErrNotReady) {
+		t.Errorf("expected ErrNotReady, found %v", err)
+	}
+	clock.Advance(time.Millisecond)
+	if _, err := second.Generate(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestShortid_onStateStoreAndClockWait_waits(t *testing.T) {
+	store := shortid.NewFileStore(filepath.Join(t.TempDir(), "state"))
+	clock := shortidtest.NewClock(time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC))
+	restored := clock.Now().Add(time.Minute)
+	if err := store.Save(restored); err != nil {
+		t.Fatal(err)
+	}
+	sid, err := shortid.NewWithOptions(shortid.WithClock(clock), shortid.WithClockPolicy(shortid.ClockWait),
+		shortid.WithStateStore(store, 0, 5*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := sid.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if issued := must(sid.Decode(id)).Time; !issued.After(restored.Add(5 * time.Millisecond)) {
+		t.Errorf("expected Id issued after %v, found %v", restored.Add(5*time.Millisecond), issued)
+	}
+	if err := sid.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if saved, err := store.Load(); err != nil || !saved.After(restored) {
+		t.Errorf("expected state after %v, found %v, %v", restored, saved, err)
+	}
+}
+
+// crashStore counts saves and ignores them once crashed, simulating a process that died.
+type crashStore struct {
+	mu      sync.Mutex
+	tm      time.Time
+	saves   int
+	crashed bool
+}
+
+func (cs *crashStore) Load() (time.Time, error) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	return cs.tm, nil
+}
+
+func (cs *crashStore) Save(tm time.Time) error {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	if !cs.crashed {
+		cs.tm = tm
+		cs.saves++
+	}
+	return nil
+}
+
+func (cs *crashStore) count() int {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	return cs.saves
+}
+
+func (cs *crashStore) crash() {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.crashed = true
+}
+
+func TestShortid_onStateStore_crashAfterIdle_noDuplicates(t *testing.T) {
+	store := &crashStore{}
+	t0 := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	clock := shortidtest.NewClock(t0)
+	first, err := shortid.NewWithOptions(shortid.WithClock(clock), shortid.WithWorker(7),
+		shortid.WithStateStore(store, time.Millisecond, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer first.Close()
+	issued := make(map[[3]uint]bool)
+	id, err := first.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := must(first.Decode(id))
+	issued[[3]uint{decoded.Ms, decoded.Worker, decoded.Counter}] = true
+
+	// idle until a checkpoint completes after the clock moved on
+	clock.Advance(10 * time.Minute)
+	saves := store.count()
+	deadline := time.Now().Add(5 * time.Second)
+	for store.count() < saves+2 {
+		if time.Now().After(deadline) {
+			t.Fatal("expected a checkpoint")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// crash before the next checkpoint
+	store.crash()
+	if id, err = first.Generate(); err != nil {
+		t.Fatal(err)
+	}
+	decoded = must(first.Decode(id))
+	issued[[3]uint{decoded.Ms, decoded.Worker, decoded.Counter}] = true
+
+	// restart with the clock set back to the last Id issued before the crash
+	clock.Advance(time.Second)
+	clock.Set(t0.Add(10 * time.Minute))
+	saved, _ := store.Load()
+	second, err := shortid.NewWithOptions(shortid.WithClock(clock), shortid.WithWorker(7),
+		shortid.WithClockPolicy(shortid.ClockWait), shortid.WithStateStore(&crashStore{tm: saved}, 0, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer second.Close()
+	for i := 0; i < 10; i++ {
+		if id, err = second.Generate(); err != nil {
+			t.Fatal(err)
+		}
+		decoded = must(second.Decode(id))
+		if issued[[3]uint{decoded.Ms, decoded.Worker, decoded.Counter}] {
+			t.Errorf("expected unique Id after restart, found %+v reissued", decoded)
+		}
+	}
+}
+
+func TestShortid_onStateStore_nil_error(t *testing.T) {
+	if _, err := shortid.NewWithOptions(shortid.WithStateStore(nil, time.Second, 0)); err == nil {
+		t.Error("expected error")
+	}
+}
@@ -0,0 +1,201 @@
+// Copyright (c) 2016-2017. Oleg Sklyar & teris.io. All rights reserved.
+// See the LICENSE file in the project root for licensing information.
+
+package shortid
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultSeparator separates the prefix from the Id in a TypedID unless the entity type implements
+// Separator.
+const DefaultSeparator = '_'
+
+// Prefixer is implemented by entity types to provide the prefix of their typed Ids, e.g. "usr".
+type Prefixer interface {
+	Prefix() string
+}
+
+// Separator can be implemented by entity types to use a separator other than DefaultSeparator.
+type Separator interface {
+	Separator() rune
+}
+
+// TypedID is a short Id prefixed with the prefix of the entity type T, e.g. usr_9NDveu-9Q for
+//
+//	type User struct{ ... }
+//
+//	func (User) Prefix() string { return "usr" }
+//
+// The prefix must consist of symbols of the alphabet (but not the separator), which keeps typed Ids
+// in the same character set as plain Ids; as the prefix never contains the separator, typed Ids are
+// split unambiguously at the first separator even if the separator is part of the alphabet. Like
+// ID, TypedID marshals to and from text, JSON and SQL, validating prefix and Id on the way in, and
+// the empty TypedID represents the absence of an Id.
+type TypedID[T Prefixer] string
+
+// NewTypedID generates a typed Id with the given generator.
+func NewTypedID[T Prefixer](sid *Shortid) (TypedID[T], error) {
+	abc := sid.Abc()
+	prefix, sep, err := typedPrefix[T](&abc)
+	if err != nil {
+		return "", err
+	}
+	id, err := sid.Generate()
+	if err != nil {
+		return "", err
+	}
+	return TypedID[T](prefix + string(sep) + id), nil
+}
+
+// MustNewTypedID acts just like NewTypedID, but panics instead of returning errors.
+func MustNewTypedID[T Prefixer](sid *Shortid) TypedID[T] {
+	id, err := NewTypedID[T](sid)
+	if err == nil {
+		return id
+	}
+	panic(err)
+}
+
+// ParseTypedID validates that the typed Id carries the prefix of T followed by an Id of the ID
+// alphabet, see ParseID and SetIDAbc. The empty string parses into the empty TypedID.
+func ParseTypedID[T Prefixer](s string) (TypedID[T], error) {
+	if s == "" {
+		return "", nil
+	}
+	abc := IDAbc()
+	prefix, sep, err := typedPrefix[T](&abc)
+	if err != nil {
+		return "", err
+	}
+	i := strings.IndexRune(s, sep)
+	if i < 0 || s[:i] != prefix {
+		return "", invalid(s, ErrInvalidPrefix, "expected prefix '%v%c'", prefix, sep)
+	}
+	id := s[i+utf8.RuneLen(sep):]
+	if id == "" {
+		return "", invalid(s, ErrInvalidLength, "expected an Id after the prefix")
+	}
+	if _, err := abc.ParseID(id); err != nil {
+		var verr *ValidationError
+		if errors.As(err, &verr) {
+			verr.ID = s
+		}
+		return "", err
+	}
+	return TypedID[T](s), nil
+}
+
+// typedPrefix returns the prefix and the separator of the entity type T validated against the
+// alphabet.
+func typedPrefix[T Prefixer](abc *Abc) (string, rune, error) {
+	var entity T
+	prefix := entity.Prefix()
+	sep := DefaultSeparator
+	if s, ok := interface{}(entity).(Separator); ok {
+		sep = s.Separator()
+	}
+	if !unicode.IsPrint(sep) || unicode.IsSpace(sep) {
+		return "", 0, fmt.Errorf("separator '%c' is not a printable symbol", sep)
+	}
+	if prefix == "" {
+		return "", 0, errors.New("expected non-empty prefix")
+	}
+	for _, r := range prefix {
+		if r == sep {
+			return "", 0, fmt.Errorf("prefix '%v' contains the separator '%c'", prefix, sep)
+		}
+		if _, ok := abc.indexOf(r); !ok {
+			return "", 0, fmt.Errorf("prefix '%v' contains symbol '%c' outside of the alphabet", prefix, r)
+		}
+	}
+	return prefix, sep, nil
+}
+
+// String returns the typed Id as string.
+func (id TypedID[T]) String() string {
+	return string(id)
+}
+
+// Prefix returns the prefix of the entity type T.
+func (id TypedID[T]) Prefix() string {
+	var entity T
+	return entity.Prefix()
+}
+
+// ID returns the Id without the prefix and the separator.
+func (id TypedID[T]) ID() ID {
+	prefix := id.Prefix()
+	if len(id) <= len(prefix) {
+		return ""
+	}
+	_, size := utf8.DecodeRuneInString(string(id[len(prefix):]))
+	return ID(id[len(prefix)+size:])
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (id TypedID[T]) MarshalText() ([]byte, error) {
+	return []byte(id), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler validating the typed Id, see ParseTypedID.
+func (id *TypedID[T]) UnmarshalText(text []byte) error {
+	parsed, err := ParseTypedID[T](string(text))
+	if err != nil {
+		return err
+	}
+	*id = parsed
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, the empty TypedID marshals to null.
+func (id TypedID[T]) MarshalJSON() ([]byte, error) {
+	if id == "" {
+		return []byte("null"), nil
+	}
+	return json.Marshal(string(id))
+}
+
+// UnmarshalJSON implements json.Unmarshaler validating the typed Id, see ParseTypedID; null
+// unmarshals to the empty TypedID.
+func (id *TypedID[T]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*id = ""
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	return id.UnmarshalText([]byte(s))
+}
+
+// Value implements driver.Valuer, the empty TypedID is stored as null.
+func (id TypedID[T]) Value() (driver.Value, error) {
+	if id == "" {
+		return nil, nil
+	}
+	return string(id), nil
+}
+
+// Scan implements sql.Scanner validating the typed Id, see ParseTypedID; null scans to the empty
+// TypedID.
+func (id *TypedID[T]) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*id = ""
+		return nil
+	case string:
+		return id.UnmarshalText([]byte(v))
+	case []byte:
+		return id.UnmarshalText(v)
+	default:
+		return fmt.Errorf("cannot scan %T into shortid.TypedID", src)
+	}
+}
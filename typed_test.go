@@ -0,0 +1,140 @@
+// Copyright (c) 2016-2017. Oleg Sklyar & teris.io. All rights reserved.
+// See the LICENSE file in the project root for licensing information.
+
+package shortid_test
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/teris-io/shortid"
+	"strings"
+	"testing"
+)
+
+type user struct{}
+
+func (user) Prefix() string { return "usr" }
+
+type order struct{}
+
+func (order) Prefix() string { return "ord" }
+
+type invoice struct{}
+
+func (invoice) Prefix() string  { return "inv" }
+func (invoice) Separator() rune { return '.' }
+
+type badPrefix struct{}
+
+func (badPrefix) Prefix() string { return "u_r" }
+
+type outsidePrefix struct{}
+
+func (outsidePrefix) Prefix() string { return "usr!" }
+
+func TestTypedID_generateAndParse(t *testing.T) {
+	id, err := shortid.NewTypedID[user](shortid.GetDefault())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(id.String(), "usr_") || len(id) < len("usr_")+9 {
+		t.Errorf("expected usr_ prefixed Id, found %v", id)
+	}
+	if id.Prefix() != "usr" || "usr_"+string(id.ID()) != id.String() {
+		t.Errorf("expected prefix usr and bare Id, found %v and %v", id.Prefix(), id.ID())
+	}
+	parsed, err := shortid.ParseTypedID[user](id.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed != id {
+		t.Errorf("expected %v, found %v", id, parsed)
+	}
+}
+
+func TestTypedID_onWrongPrefix_error(t *testing.T) {
+	id := shortid.MustNewTypedID[order](shortid.GetDefault())
+	if _, err := shortid.ParseTypedID[user](id.String()); !errors.Is(err, shortid.ErrInvalidPrefix) {
+		t.Errorf("expected ErrInvalidPrefix, found %v", err)
+	}
+	if _, err := shortid.ParseTypedID[user]("usr"); !errors.Is(err, shortid.ErrInvalidPrefix) {
+		t.Errorf("expected ErrInvalidPrefix, found %v", err)
+	}
+	if _, err := shortid.ParseTypedID[user]("usr_"); !errors.Is(err, shortid.ErrInvalidLength) {
+		t.Errorf("expected ErrInvalidLength, found %v", err)
+	}
+	var verr *shortid.ValidationError
+	if _, err := shortid.ParseTypedID[user]("usr_ggWP3*LZg"); !errors.As(err, &verr) || verr.ID != "usr_ggWP3*LZg" {
+		t.Errorf("expected ValidationError for the typed Id, found %v", err)
+	}
+}
+
+func TestTypedID_onSeparatorInIdPart_splitsAtFirst(t *testing.T) {
+	id := "usr_gg_P3JLZg"
+	parsed, err := shortid.ParseTypedID[user](id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.ID() != "gg_P3JLZg" {
+		t.Errorf("expected gg_P3JLZg, found %v", parsed.ID())
+	}
+}
+
+func TestTypedID_customSeparator(t *testing.T) {
+	id := shortid.MustNewTypedID[invoice](shortid.GetDefault())
+	if !strings.HasPrefix(id.String(), "inv.") {
+		t.Errorf("expected inv. prefixed Id, found %v", id)
+	}
+	if _, err := shortid.ParseTypedID[invoice](id.String()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTypedID_onInvalidPrefix_error(t *testing.T) {
+	if _, err := shortid.NewTypedID[badPrefix](shortid.GetDefault()); err == nil {
+		t.Error("expected error for prefix containing the separator")
+	}
+	if _, err := shortid.NewTypedID[outsidePrefix](shortid.GetDefault()); err == nil {
+		t.Error("expected error for prefix outside of the alphabet")
+	}
+}
+
+func TestTypedID_jsonRoundTrip(t *testing.T) {
+	type entity struct {
+		ID    shortid.TypedID[user]  `json:"id"`
+		Order shortid.TypedID[order] `json:"order"`
+	}
+	e := entity{ID: shortid.MustNewTypedID[user](shortid.GetDefault())}
+	data, err := json.Marshal(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded entity
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded != e {
+		t.Errorf("expected %v, found %v", e, decoded)
+	}
+	if err := json.Unmarshal([]byte(`{"id":"ord_ggWP3JLZg"}`), &decoded); !errors.Is(err, shortid.ErrInvalidPrefix) {
+		t.Errorf("expected ErrInvalidPrefix, found %v", err)
+	}
+}
+
+func TestTypedID_sqlRoundTrip(t *testing.T) {
+	id := shortid.MustNewTypedID[user](shortid.GetDefault())
+	value, err := id.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var scanned shortid.TypedID[user]
+	if err := scanned.Scan(value); err != nil {
+		t.Fatal(err)
+	}
+	if scanned != id {
+		t.Errorf("expected %v, found %v", id, scanned)
+	}
+	if err := scanned.Scan(nil); err != nil || scanned != "" {
+		t.Errorf("expected empty Id, found %v, %v", scanned, err)
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2016-2017. Oleg Sklyar & teris.io. All rights reserved.
+// See the LICENSE file in the project root for licensing information.
+
+package shortid
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors identifying the check that failed in Validate; use errors.Is to test for them.
+var (
+	// ErrInvalidLength marks Ids shorter than the Layout of the generator (9 symbols by default) or
+	// carrying a counter above the largest the generator can reach or not in its shortest encoding.
+	ErrInvalidLength = errors.New("invalid length")
+	// ErrInvalidSymbol marks Ids containing symbols outside of the alphabet of the generator or not
+	// valid at their position.
+	ErrInvalidSymbol = errors.New("invalid symbol")
+	// ErrInvalidWorker marks Ids with a worker outside of the worker range of the generator.
+	ErrInvalidWorker = errors.New("invalid worker")
+	// ErrFutureTime marks Ids with a timestamp after the current time of the generator's clock.
+	ErrFutureTime = errors.New("time in the future")
+	// ErrInvalidPrefix marks typed Ids without the prefix of their entity type, see TypedID.
+	ErrInvalidPrefix = errors.New("invalid prefix")
+)
+
+// ValidationError is returned by Validate and Decode for Ids that fail validation. It wraps one of
+// ErrInvalidLength, ErrInvalidSymbol, ErrInvalidWorker, ErrFutureTime or ErrInvalidPrefix.
+type ValidationError struct {
+	ID     string // the Id that failed validation
+	Err    error  // the check that failed
+	Detail string // description of the failure
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid id '%v': %v: %v", e.ID, e.Err, e.Detail)
+}
+
+// Unwrap returns the check that failed.
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+func invalid(id string, err error, format string, args ...interface{}) *ValidationError {
+	return &ValidationError{ID: id, Err: err, Detail: fmt.Sprintf(format, args...)}
+}
+
+// Validate checks that an Id, e.g. received from untrusted input, could have been generated by
+// this generator: that it consists of at least Layout().Len() symbols of the alphabet, that it
+// carries a counter the generator can reach, that it encodes a worker in the worker range and that
+// its timestamp is not in the future relative to the clock of the generator. It returns a
+// *ValidationError describing the first failed check.
+func (sid *Shortid) Validate(id string) error {
+	decoded, err := sid.Decode(id)
+	if err != nil {
+		return err
+	}
+	if maxWorker := sid.layout.MaxWorker(); decoded.Worker > maxWorker {
+		return invalid(id, ErrInvalidWorker, "expected worker in the range [0,%v], found %v", maxWorker, decoded.Worker)
+	}
+	if now := sid.clock.Now(); decoded.Time.After(now) {
+		return invalid(id, ErrFutureTime, "generated at %v, now %v", decoded.Time, now)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2016-2017. Oleg Sklyar & teris.io. All rights reserved.
+// See the LICENSE file in the project root for licensing information.
+
+package shortid_test
+
+import (
+	"errors"
+	"github.com/teris-io/shortid"
+	"github.com/teris-io/shortid/shortidtest"
+	"testing"
+	"time"
+)
+
+func TestShortid_onValidate_success(t *testing.T) {
+	sid := shortid.MustNew(0, shortid.DefaultABC, 1)
+	for _, id := range append(sid.MustGenerateN(100), sid.MustGenerate()) {
+		if err := sid.Validate(id); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestShortid_onValidate_invalidLength_error(t *testing.T) {
+	sid := shortid.MustNew(0, shortid.DefaultABC, 1)
+	err := sid.Validate("gzmZM7VI")
+	if !errors.Is(err, shortid.ErrInvalidLength) {
+		t.Errorf("expected ErrInvalidLength, found %v", err)
+	}
+	var verr *shortid.ValidationError
+	if !errors.As(err, &verr) || verr.ID != "gzmZM7VI" {
+		t.Errorf("expected *ValidationError for the id, found %v", err)
+	}
+}
+
+func TestShortid_onValidate_tooLong_error(t *testing.T) {
+	sid := shortid.MustNew(0, shortid.DefaultABC, 1)
+	id, err := sid.Encode(shortid.DecodedID{Ms: 123456789})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"0000000000000000000000000000000", id + "zzzzzzz"} {
+		err := sid.Validate(id)
+		var verr *shortid.ValidationError
+		if !errors.Is(err, shortid.ErrInvalidLength) || !errors.As(err, &verr) {
+			t.Errorf("%v: expected *ValidationError with ErrInvalidLength, found %v", id, err)
+		}
+	}
+}
+
+func TestShortid_onValidate_counterAboveMax_error(t *testing.T) {
+	// 5 counter symbols of 5 bits exceed the 24-bit counter of the Base58 layout
+	sid := shortid.MustNew(0, shortid.Base58ABC, 1)
+	id, err := sid.Encode(shortid.DecodedID{Ms: 123456789})
+	if err != nil {
+		t.Fatal(err)
+	}
+	abc := sid.Abc()
+	counter, err := abc.Encode(1<<24, 5, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sid.Validate(id + string(counter)); !errors.Is(err, shortid.ErrInvalidLength) {
+		t.Errorf("expected ErrInvalidLength, found %v", err)
+	}
+	max, err := abc.Encode(1<<24-1, 5, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sid.Validate(id + string(max)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestShortid_onValidate_nonCanonicalCounter_error(t *testing.T) {
+	sid := shortid.MustNew(0, shortid.DefaultABC, 1)
+	id, err := sid.Encode(shortid.DecodedID{Ms: 123456789})
+	if err != nil {
+		t.Fatal(err)
+	}
+	abc := sid.Abc()
+	symbols := []rune(abc.Alphabet())
+	zero, one, max := string(symbols[0]), string(symbols[1]), string(symbols[63])
+	for _, id := range []string{id + zero, id + max + zero, id + max + zero + zero, id + one + zero + zero} {
+		if err := sid.Validate(id); !errors.Is(err, shortid.ErrInvalidLength) {
+			t.Errorf("%v: expected ErrInvalidLength, found %v", id, err)
+		}
+	}
+	if _, err := sid.Compare(id+max, id+max+zero); !errors.Is(err, shortid.ErrInvalidLength) {
+		t.Errorf("expected ErrInvalidLength, found %v", err)
+	}
+	if err := sid.Validate(id + max + one); err != nil {
+		t.Error(err)
+	}
+
+	// Base58 counter symbols encode 5 bits, the upper symbols are not valid in the counter
+	sid = shortid.MustNew(0, shortid.Base58ABC, 1)
+	if id, err = sid.Encode(shortid.DecodedID{Ms: 123456789}); err != nil {
+		t.Fatal(err)
+	}
+	if err := sid.Validate(id + string([]rune(sid.Abc().Alphabet())[33])); !errors.Is(err, shortid.ErrInvalidSymbol) {
+		t.Errorf("expected ErrInvalidSymbol, found %v", err)
+	}
+}
+
+func TestShortid_onValidate_invalidSymbol_error(t *testing.T) {
+	sid := shortid.MustNew(0, shortid.DefaultABC, 1)
+	if err := sid.Validate("gzmZM7VI/"); !errors.Is(err, shortid.ErrInvalidSymbol) {
+		t.Errorf("expected ErrInvalidSymbol, found %v", err)
+	}
+	if err := sid.Validate("gz'); DROP TABLE ids; --"); !errors.Is(err, shortid.ErrInvalidSymbol) {
+		t.Errorf("expected ErrInvalidSymbol, found %v", err)
+	}
+}
+
+func TestShortid_onValidate_futureTime_error(t *testing.T) {
+	clock := shortidtest.NewClock(time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC))
+	sid, err := shortid.NewWithOptions(shortid.WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(time.Millisecond)
+	id := sid.MustGenerate()
+	clock.Advance(-time.Millisecond)
+	if err := sid.Validate(id); !errors.Is(err, shortid.ErrFutureTime) {
+		t.Errorf("expected ErrFutureTime, found %v", err)
+	}
+	clock.Advance(time.Millisecond)
+	if err := sid.Validate(id); err != nil {
+		t.Error(err)
+	}
+}
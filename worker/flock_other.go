@@ -0,0 +1,18 @@
+// Copyright (c) 2016-2017. Oleg Sklyar & teris.io. All rights reserved.
+// See the LICENSE file in the project root for licensing information.
+
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package worker
+
+import (
+	"errors"
+	"os"
+)
+
+var errLocked = errors.New("file locked by another process")
+
+// lockFile is not supported on this platform.
+func lockFile(string) (*os.File, error) {
+	return nil, errors.New("file locks are not supported on this platform")
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2016-2017. Oleg Sklyar & teris.io. All rights reserved.
+// See the LICENSE file in the project root for licensing information.
+
+package worker
+
+import (
+	"context"
+	"github.com/teris-io/shortid"
+	"sync"
+)
+
+// Registry leases worker numbers, [0,31] by default, to generators within a single process, e.g.
+// to generators of independent components sharing a data space, and in tests.
+type Registry struct {
+	mx      sync.Mutex
+	leases  map[uint8]*registryLease
+	workers int
+}
+
+// NewRegistry constructs an empty in-memory registry.
+func NewRegistry(opts ...Option) (*Registry, error) {
+	c, err := newConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Registry{leases: make(map[uint8]*registryLease), workers: c.workers}, nil
+}
+
+// Acquire leases the lowest free worker number.
+func (r *Registry) Acquire(ctx context.Context) (uint8, shortid.Lease, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, nil, err
+	}
+	r.mx.Lock()
+	defer r.mx.Unlock()
+	for n := 0; n < r.workers; n++ {
+		number := uint8(n)
+		if _, ok := r.leases[number]; ok {
+			continue
+		}
+		lease := &registryLease{registry: r, number: number, lost: make(chan struct{})}
+		r.leases[number] = lease
+		return number, lease, nil
+	}
+	return 0, nil, ErrNoFreeWorker
+}
+
+// Revoke frees the worker number, marking its current lease as lost.
+func (r *Registry) Revoke(number uint8) {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+	if lease, ok := r.leases[number]; ok {
+		delete(r.leases, number)
+		close(lease.lost)
+	}
+}
+
+type registryLease struct {
+	registry *Registry
+	number   uint8
+	lost     chan struct{}
+}
+
+func (l *registryLease) Lost() <-chan struct{} {
+	return l.lost
+}
+
+func (l *registryLease) Release() error {
+	r := l.registry
+	r.mx.Lock()
+	defer r.mx.Unlock()
+	if r.leases[l.number] == l {
+		delete(r.leases, l.number)
+	}
+	return nil
+}
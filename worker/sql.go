@@ -0,0 +1,239 @@
+// Copyright (c) 2016-2017. Oleg Sklyar & teris.io. All rights reserved.
+// See the LICENSE file in the project root for licensing information.
+
+package worker
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/teris-io/shortid"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultTable is the name of the table used by SQLLeaser unless configured otherwise.
+const DefaultTable = "shortid_workers"
+
+// SQLLeaser leases worker numbers, [0,31] by default, to generators across hosts using rows of a
+// table in a shared database. Leases expire after a time-to-live unless renewed, which the lease
+// does in the background every quarter of the time-to-live. A lease that cannot be renewed is
+// lost a third of the time-to-live before it expires, which keeps generators on hosts with clocks
+// apart by less than that margin from issuing Ids with the same worker number. The table can be
+// created with CreateTable.
+type SQLLeaser struct {
+	db      *sql.DB
+	ttl     time.Duration
+	table   string
+	dollar  bool
+	workers int
+}
+
+// NewSQLLeaser constructs a leaser storing leases with the given time-to-live in db.
+func NewSQLLeaser(db *sql.DB, ttl time.Duration, opts ...Option) (*SQLLeaser, error) {
+	if db == nil {
+		return nil, errors.New("expected non-nil database")
+	}
+	if ttl <= 0 {
+		return nil, fmt.Errorf("expected positive lease time-to-live, found %v", ttl)
+	}
+	c, err := newConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &SQLLeaser{db: db, ttl: ttl, table: c.table, dollar: c.dollar, workers: c.workers}, nil
+}
+
+// CreateTable creates the table holding the leases if it does not exist.
+func (l *SQLLeaser) CreateTable(ctx context.Context) error {
+	_, err := l.db.ExecContext(ctx, l.query("CREATE TABLE IF NOT EXISTS %s "+
+		"(worker INTEGER PRIMARY KEY, owner VARCHAR(32) NOT NULL, expires_at BIGINT NOT NULL)"))
+	return err
+}
+
+// Acquire leases the lowest worker number that has no row in the table or whose lease expired.
+func (l *SQLLeaser) Acquire(ctx context.Context) (uint8, shortid.Lease, error) {
+	owner, err := newOwner()
+	if err != nil {
+		return 0, nil, err
+	}
+	expiries, err := l.expiries(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	for n := 0; n < l.workers; n++ {
+		number := uint8(n)
+		now := time.Now()
+		expiresAt := now.Add(l.ttl)
+		var res sql.Result
+		if expiry, ok := expiries[number]; !ok {
+			res, err = l.db.ExecContext(ctx, l.query("INSERT INTO %s (worker, owner, expires_at) VALUES (?, ?, ?)"),
+				number, owner, expiresAt.UnixMilli())
+			if err != nil {
+				if ctx.Err() != nil {
+					return 0, nil, ctx.Err()
+				}
+				continue // inserted concurrently by another leaser
+			}
+		} else if expiry < now.UnixMilli() {
+			res, err = l.db.ExecContext(ctx, l.query("UPDATE %s SET owner = ?, expires_at = ? WHERE worker = ? AND expires_at = ?"),
+				owner, expiresAt.UnixMilli(), number, expiry)
+			if err != nil {
+				return 0, nil, err
+			}
+		} else {
+			continue
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			return 0, nil, err
+		} else if n != 1 {
+			continue // taken over concurrently by another leaser
+		}
+		lease := &sqlLease{
+			leaser:    l,
+			number:    number,
+			owner:     owner,
+			expiresAt: expiresAt,
+			lost:      make(chan struct{}),
+			stop:      make(chan struct{}),
+			done:      make(chan struct{}),
+		}
+		go lease.renew()
+		return number, lease, nil
+	}
+	return 0, nil, ErrNoFreeWorker
+}
+
+func (l *SQLLeaser) expiries(ctx context.Context) (map[uint8]int64, error) {
+	rows, err := l.db.QueryContext(ctx, l.query("SELECT worker, expires_at FROM %s"))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := make(map[uint8]int64)
+	for rows.Next() {
+		var number uint8
+		var expiry int64
+		if err := rows.Scan(&number, &expiry); err != nil {
+			return nil, err
+		}
+		res[number] = expiry
+	}
+	return res, rows.Err()
+}
+
+// query inserts the table name into the query and rewrites its placeholders if configured.
+func (l *SQLLeaser) query(format string) string {
+	query := fmt.Sprintf(format, l.table)
+	if !l.dollar {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			fmt.Fprintf(&b, "$%d", n)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func newOwner() (string, error) {
+	var owner [16]byte
+	if _, err := rand.Read(owner[:]); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(owner[:]), nil
+}
+
+type sqlLease struct {
+	leaser    *SQLLeaser
+	number    uint8
+	owner     string
+	expiresAt time.Time
+	lost      chan struct{}
+	stop      chan struct{}
+	done      chan struct{}
+	once      sync.Once
+}
+
+func (l *sqlLease) Lost() <-chan struct{} {
+	return l.lost
+}
+
+func (l *sqlLease) Release() error {
+	var err error
+	l.once.Do(func() {
+		close(l.stop)
+		<-l.done
+		select {
+		case <-l.lost:
+			return // the row belongs to somebody else or is gone
+		default:
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), l.leaser.ttl)
+		defer cancel()
+		_, err = l.leaser.db.ExecContext(ctx, l.leaser.query("DELETE FROM %s WHERE worker = ? AND owner = ?"),
+			l.number, l.owner)
+	})
+	return err
+}
+
+func (l *sqlLease) renew() {
+	defer close(l.done)
+	ticker := time.NewTicker(l.leaser.ttl / 4)
+	defer ticker.Stop()
+	deadline := time.NewTimer(time.Until(l.deadline()))
+	defer deadline.Stop()
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-deadline.C:
+			close(l.lost)
+			return
+		case <-ticker.C:
+			if !l.extend() {
+				close(l.lost)
+				return
+			}
+			if !deadline.Stop() {
+				<-deadline.C
+			}
+			deadline.Reset(time.Until(l.deadline()))
+		}
+	}
+}
+
+// deadline returns the time at which the lease is considered lost unless renewed, a third of the
+// time-to-live before it expires in the database.
+func (l *sqlLease) deadline() time.Time {
+	return l.expiresAt.Add(-l.leaser.ttl / 3)
+}
+
+// extend renews the lease reporting false once it is lost: if its row was taken over or if it
+// could not be renewed before the deadline.
+func (l *sqlLease) extend() bool {
+	ctx, cancel := context.WithDeadline(context.Background(), l.deadline())
+	defer cancel()
+	expiresAt := time.Now().Add(l.leaser.ttl)
+	res, err := l.leaser.db.ExecContext(ctx, l.leaser.query("UPDATE %s SET expires_at = ? WHERE worker = ? AND owner = ?"),
+		expiresAt.UnixMilli(), l.number, l.owner)
+	if err != nil {
+		return time.Now().Before(l.deadline())
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return time.Now().Before(l.deadline())
+	} else if n != 1 {
+		return false
+	}
+	l.expiresAt = expiresAt
+	return true
+}
@@ -0,0 +1,8 @@
+// Copyright (c) 2016-2017. Oleg Sklyar & teris.io. All rights reserved.
+// See the LICENSE file in the project root for licensing information.
+
+// Package sqlitetest runs the tests of worker.SQLLeaser against SQLite. It is a separate module so
+// that the SQLite driver does not become a dependency of the shortid module; run the tests with
+//
+//	cd worker/sqlitetest && go test ./...
+package sqlitetest
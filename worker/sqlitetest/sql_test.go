@@ -0,0 +1,188 @@
+// Copyright (c) 2016-2017. Oleg Sklyar & teris.io. All rights reserved.
+// See the LICENSE file in the project root for licensing information.
+
+package sqlitetest_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/teris-io/shortid"
+	"github.com/teris-io/shortid/worker"
+	_ "modernc.org/sqlite"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSQLLeaser_distinctWorkersAcrossLeasers(t *testing.T) {
+	db := openDB(t)
+	first := newSQLLeaser(t, db, time.Minute)
+	second := newSQLLeaser(t, db, time.Minute)
+	n1, l1, err := first.Acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	n2, l2, err := second.Acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n1 != 0 || n2 != 1 {
+		t.Errorf("expected workers 0 and 1, found %v and %v", n1, n2)
+	}
+	if err := l1.Release(); err != nil {
+		t.Fatal(err)
+	}
+	n3, l3, err := second.Acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n3 != 0 {
+		t.Errorf("expected released worker 0, found %v", n3)
+	}
+	_ = l2.Release()
+	_ = l3.Release()
+}
+
+func TestSQLLeaser_onExhausted_error(t *testing.T) {
+	db := openDB(t)
+	leaser := newSQLLeaser(t, db, time.Minute)
+	for i := 0; i < worker.Workers; i++ {
+		if _, _, err := leaser.Acquire(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, _, err := leaser.Acquire(context.Background()); !errors.Is(err, worker.ErrNoFreeWorker) {
+		t.Errorf("expected ErrNoFreeWorker, found %v", err)
+	}
+}
+
+func TestSQLLeaser_withWorkers_limitsRange(t *testing.T) {
+	db := openDB(t)
+	leaser, err := worker.NewSQLLeaser(db, time.Minute, worker.WithWorkers(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := leaser.CreateTable(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, _, err := leaser.Acquire(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, _, err := leaser.Acquire(context.Background()); !errors.Is(err, worker.ErrNoFreeWorker) {
+		t.Errorf("expected ErrNoFreeWorker, found %v", err)
+	}
+}
+
+func TestSQLLeaser_onExpired_reclaimed(t *testing.T) {
+	db := openDB(t)
+	leaser := newSQLLeaser(t, db, time.Minute)
+	if _, err := db.Exec("INSERT INTO shortid_workers (worker, owner, expires_at) VALUES (?, ?, ?)",
+		0, "crashed", time.Now().Add(-time.Second).UnixMilli()); err != nil {
+		t.Fatal(err)
+	}
+	number, lease, err := leaser.Acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lease.Release()
+	if number != 0 {
+		t.Errorf("expected expired worker 0, found %v", number)
+	}
+}
+
+func TestSQLLeaser_onTakenOver_generatorStops(t *testing.T) {
+	db := openDB(t)
+	leaser := newSQLLeaser(t, db, 30*time.Millisecond)
+	sid, err := shortid.NewWithOptions(shortid.WithWorkerLeaser(context.Background(), leaser))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sid.Close()
+	if _, err := sid.Generate(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec("UPDATE shortid_workers SET owner = ?, expires_at = ? WHERE worker = ?",
+		"other", time.Now().Add(time.Minute).UnixMilli(), sid.Worker()); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for err == nil && time.Now().Before(deadline) {
+		_, err = sid.Generate()
+		time.Sleep(time.Millisecond)
+	}
+	if !errors.Is(err, shortid.ErrLeaseLost) {
+		t.Errorf("expected ErrLeaseLost, found %v", err)
+	}
+}
+
+func TestSQLLeaser_dollarPlaceholders(t *testing.T) {
+	db := openDB(t)
+	leaser, err := worker.NewSQLLeaser(db, time.Minute, worker.WithDollarPlaceholders(), worker.WithTable("leases"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := leaser.CreateTable(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	_, lease, err := leaser.Acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var owners int
+	if err := db.QueryRow("SELECT COUNT(*) FROM leases").Scan(&owners); err != nil || owners != 1 {
+		t.Errorf("expected 1 lease, found %v, %v", owners, err)
+	}
+	if err := lease.Release(); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.QueryRow("SELECT COUNT(*) FROM leases").Scan(&owners); err != nil || owners != 0 {
+		t.Errorf("expected no lease, found %v, %v", owners, err)
+	}
+}
+
+func newSQLLeaser(t *testing.T, db *sql.DB, ttl time.Duration) *worker.SQLLeaser {
+	leaser, err := worker.NewSQLLeaser(db, ttl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := leaser.CreateTable(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return leaser
+}
+
+func openDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite", "file:"+filepath.Join(t.TempDir(), "leases.db")+"?_pragma=busy_timeout(5000)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func TestSQLLeaser_onRenewalFailing_lostBeforeExpiry(t *testing.T) {
+	db := openDB(t)
+	ttl := 600 * time.Millisecond
+	leaser := newSQLLeaser(t, db, ttl)
+	_, lease, err := leaser.Acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lease.Release()
+	// renewals fail from now on, the last successful one expires at the latest within ttl
+	dropped := time.Now()
+	if _, err := db.Exec("DROP TABLE shortid_workers"); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-lease.Lost():
+		if elapsed := time.Since(dropped); elapsed > ttl*2/3+50*time.Millisecond {
+			t.Errorf("expected lease lost a third of the ttl before expiry, lost after %v", elapsed)
+		}
+	case <-time.After(2 * ttl):
+		t.Error("expected lease lost")
+	}
+}
@@ -0,0 +1,226 @@
+// Copyright (c) 2016-2017. Oleg Sklyar & teris.io. All rights reserved.
+// See the LICENSE file in the project root for licensing information.
+
+// Package worker assigns worker numbers to short Id generators automatically, so that processes
+// generating Ids into the same data space never share a worker number by accident.
+//
+// Lease picks a free worker number [0,31] using lock files in a directory shared by all processes
+// on a host:
+//
+//	w, err := worker.Lease("/var/run/myapp", shortid.WithSeed(2342))
+//	if err != nil {
+//		...
+//	}
+//	defer w.Close()
+//	id, err := w.Shortid().Generate()
+//
+// Generators on several hosts, or several generators within a process, lease worker numbers with
+// the shortid.WorkerLeaser implementations SQLLeaser and Registry instead:
+//
+//	leaser, err := worker.NewSQLLeaser(db, time.Minute)
+//	...
+//	sid, err := shortid.NewWithOptions(shortid.WithWorkerLeaser(ctx, leaser))
+//	...
+//	defer sid.Close()
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/teris-io/shortid"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Workers is the number of worker numbers available for leasing unless configured otherwise, the
+// worker range [0,31] of the default Layout.
+const Workers = 32
+
+// MaxWorkers is the largest number of worker numbers that can be leased, see WithWorkers.
+const MaxWorkers = 256
+
+// RenewInterval is the period at which the modification time of a leased lock file is renewed,
+// which lets operators tell live leases from stale lock files.
+var RenewInterval = 10 * time.Second
+
+// ErrNoFreeWorker is returned by Lease if all worker numbers are leased.
+var ErrNoFreeWorker = errors.New("no free worker number")
+
+// Option configures a leaser.
+type Option func(*config) error
+
+// config holds the settings of the leasers, table and dollar are only used by SQLLeaser.
+type config struct {
+	workers int
+	table   string
+	dollar  bool
+}
+
+// WithWorkers sets the number of worker numbers available for leasing (default: Workers), up to
+// MaxWorkers. Generators with a wider worker field (see shortid.WithWorkerSymbols) can use more
+// than the default 32; a generator fails to construct if it is leased a worker outside its range.
+func WithWorkers(n int) Option {
+	return func(c *config) error {
+		if n < 1 || n > MaxWorkers {
+			return fmt.Errorf("expected number of workers in the range [1,%v], found %v", MaxWorkers, n)
+		}
+		c.workers = n
+		return nil
+	}
+}
+
+// WithTable sets the name of the table holding the leases of an SQLLeaser.
+func WithTable(table string) Option {
+	return func(c *config) error {
+		c.table = table
+		return nil
+	}
+}
+
+// WithDollarPlaceholders makes an SQLLeaser use $1, $2, ... query placeholders, as required e.g.
+// by PostgreSQL drivers, instead of ?.
+func WithDollarPlaceholders() Option {
+	return func(c *config) error {
+		c.dollar = true
+		return nil
+	}
+}
+
+func newConfig(opts []Option) (config, error) {
+	c := config{workers: Workers, table: DefaultTable}
+	for _, opt := range opts {
+		if err := opt(&c); err != nil {
+			return c, err
+		}
+	}
+	return c, nil
+}
+
+// Worker holds the lease of a worker number and the generator using it.
+type Worker struct {
+	sid *shortid.Shortid
+}
+
+// Lease leases a free worker number [0,31] by locking one of the files worker-NN.lock in dir
+// (created if missing) and constructs a generator for it with the given options. The lock is held
+// until Close is called or the process exits, so worker numbers of crashed processes are freed
+// automatically. All processes sharing the data space must use the same dir, which must be on a
+// local file system supporting flock.
+func Lease(dir string, opts ...shortid.Option) (*Worker, error) {
+	leaser, err := NewFileLeaser(dir)
+	if err != nil {
+		return nil, err
+	}
+	sid, err := shortid.NewWithOptions(append(opts, shortid.WithWorkerLeaser(context.Background(), leaser))...)
+	if err != nil {
+		return nil, err
+	}
+	return &Worker{sid: sid}, nil
+}
+
+// Number returns the leased worker number.
+func (w *Worker) Number() uint {
+	return w.sid.Worker()
+}
+
+// Shortid returns the generator using the leased worker number. It must not be used after Close.
+func (w *Worker) Shortid() *shortid.Shortid {
+	return w.sid
+}
+
+// Close releases the worker number.
+func (w *Worker) Close() error {
+	return w.sid.Close()
+}
+
+// FileLeaser leases worker numbers, [0,31] by default, by locking files in a directory shared by
+// all processes on a host, see Lease.
+type FileLeaser struct {
+	dir     string
+	workers int
+}
+
+// NewFileLeaser constructs a leaser locking the files worker-NN.lock in dir.
+func NewFileLeaser(dir string, opts ...Option) (*FileLeaser, error) {
+	c, err := newConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &FileLeaser{dir: dir, workers: c.workers}, nil
+}
+
+// Acquire leases a free worker number by locking its file. The lease is only lost when the process
+// exits.
+func (l *FileLeaser) Acquire(ctx context.Context) (uint8, shortid.Lease, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, nil, err
+	}
+	if err := os.MkdirAll(l.dir, 0o755); err != nil {
+		return 0, nil, err
+	}
+	for n := 0; n < l.workers; n++ {
+		number := uint8(n)
+		file, err := lockFile(filepath.Join(l.dir, fmt.Sprintf("worker-%02d.lock", number)))
+		if errors.Is(err, errLocked) {
+			continue
+		} else if err != nil {
+			return 0, nil, err
+		}
+		lease := &fileLease{
+			file: file,
+			lost: make(chan struct{}),
+			stop: make(chan struct{}),
+			done: make(chan struct{}),
+		}
+		lease.writeOwner()
+		go lease.renew()
+		return number, lease, nil
+	}
+	return 0, nil, ErrNoFreeWorker
+}
+
+type fileLease struct {
+	file *os.File
+	lost chan struct{} // never closed: the lock is held until released
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+func (l *fileLease) Lost() <-chan struct{} {
+	return l.lost
+}
+
+func (l *fileLease) Release() error {
+	var err error
+	l.once.Do(func() {
+		close(l.stop)
+		<-l.done
+		err = l.file.Close() // closing the file releases the lock
+	})
+	return err
+}
+
+func (l *fileLease) writeOwner() {
+	if err := l.file.Truncate(0); err == nil {
+		_, _ = l.file.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0)
+	}
+}
+
+func (l *fileLease) renew() {
+	defer close(l.done)
+	ticker := time.NewTicker(RenewInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-l.stop:
+			return
+		case tm := <-ticker.C:
+			_ = os.Chtimes(l.file.Name(), tm, tm)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright (c) 2016-2017. Oleg Sklyar & teris.io. All rights reserved.
+// See the LICENSE file in the project root for licensing information.
+
+package worker_test
+
+import (
+	"context"
+	"errors"
+	"github.com/teris-io/shortid"
+	"github.com/teris-io/shortid/worker"
+	"path/filepath"
+	"testing"
+)
+
+func TestLease_distinctWorkersUntilExhausted(t *testing.T) {
+	dir := t.TempDir()
+	var workers []*worker.Worker
+	defer func() {
+		for _, w := range workers {
+			_ = w.Close()
+		}
+	}()
+	for i := uint(0); i < worker.Workers; i++ {
+		w, err := worker.Lease(dir, shortid.WithSeed(2342))
+		if err != nil {
+			t.Fatal(err)
+		}
+		workers = append(workers, w)
+		if w.Number() != i || w.Shortid().Worker() != i {
+			t.Errorf("expected worker %v, found %v", i, w.Number())
+		}
+	}
+	if _, err := worker.Lease(dir); !errors.Is(err, worker.ErrNoFreeWorker) {
+		t.Errorf("expected ErrNoFreeWorker, found %v", err)
+	}
+	if err := workers[7].Close(); err != nil {
+		t.Fatal(err)
+	}
+	w, err := worker.Lease(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	workers = append(workers, w)
+	if w.Number() != 7 {
+		t.Errorf("expected released worker 7, found %v", w.Number())
+	}
+}
+
+func TestLease_generatesWithOptions(t *testing.T) {
+	w, err := worker.Lease(filepath.Join(t.TempDir(), "nested"), shortid.WithAlphabet(shortid.Crockford32ABC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	id := w.Shortid().MustGenerate()
+	if len(id) != 12 {
+		t.Errorf("expected id of 12 symbols, found %v", id)
+	}
+	if decoded, err := w.Shortid().Decode(id); err != nil {
+		t.Error(err)
+	} else if decoded.Worker != w.Number() {
+		t.Errorf("expected worker %v, found %v", w.Number(), decoded.Worker)
+	}
+}
+
+func TestLease_withInvalidOptions_releasesLock(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := worker.Lease(dir, shortid.WithAlphabet("abc")); err == nil {
+		t.Fatal("expected error")
+	}
+	w, err := worker.Lease(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	if w.Number() != 0 {
+		t.Errorf("expected worker 0, found %v", w.Number())
+	}
+}
+
+func TestWorker_onClose_idempotent(t *testing.T) {
+	w, err := worker.Lease(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Error(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRegistry_onRevoke_generatorStops(t *testing.T) {
+	registry, err := worker.NewRegistry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := shortid.NewWithOptions(shortid.WithWorkerLeaser(context.Background(), registry))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer first.Close()
+	second, err := shortid.NewWithOptions(shortid.WithWorkerLeaser(context.Background(), registry))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Worker() != 0 || second.Worker() != 1 {
+		t.Errorf("expected workers 0 and 1, found %v and %v", first.Worker(), second.Worker())
+	}
+	registry.Revoke(0)
+	if _, err := first.Generate(); !errors.Is(err, shortid.ErrLeaseLost) {
+		t.Errorf("expected ErrLeaseLost, found %v", err)
+	}
+	if _, err := second.Generate(); err != nil {
+		t.Error(err)
+	}
+	if err := second.Close(); err != nil {
+		t.Fatal(err)
+	}
+	number, _, err := registry.Acquire(context.Background())
+	if err != nil || number != 0 {
+		t.Errorf("expected worker 0, found %v, %v", number, err)
+	}
+}
+
+func TestRegistry_withWorkers_leasesUpToMaxWorkers(t *testing.T) {
+	registry, err := worker.NewRegistry(worker.WithWorkers(worker.MaxWorkers))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < worker.MaxWorkers; i++ {
+		number, _, err := registry.Acquire(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if int(number) != i {
+			t.Errorf("expected worker %v, found %v", i, number)
+		}
+	}
+	if _, _, err := registry.Acquire(context.Background()); !errors.Is(err, worker.ErrNoFreeWorker) {
+		t.Errorf("expected ErrNoFreeWorker, found %v", err)
+	}
+}
+
+func TestRegistry_withWorkers_wideWorkerField(t *testing.T) {
+	registry, err := worker.NewRegistry(worker.WithWorkers(100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 99; i++ {
+		if _, _, err := registry.Acquire(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := shortid.NewWithOptions(shortid.WithWorkerLeaser(context.Background(), registry)); err == nil {
+		t.Error("expected error for worker 99 outside of the default worker range")
+	}
+	sid, err := shortid.NewWithOptions(shortid.WithWorkerSymbols(2), shortid.WithWorkerLeaser(context.Background(), registry))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sid.Close()
+	if sid.Worker() != 99 {
+		t.Errorf("expected worker 99, found %v", sid.Worker())
+	}
+}
+
+func TestWithWorkers_outOfRange_error(t *testing.T) {
+	for _, n := range []int{0, worker.MaxWorkers + 1} {
+		if _, err := worker.NewRegistry(worker.WithWorkers(n)); err == nil {
+			t.Errorf("%v: expected error", n)
+		}
+	}
+}